@@ -0,0 +1,26 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoDeadline = errors.New("parent context has no deadline")
+
+// BudgetedContext gives a query fraction of the time parent has left, so one slow
+// query can not use up the whole request budget
+func BudgetedContext(parent context.Context, fraction float64) (context.Context, context.CancelFunc, error) {
+	if fraction <= 0 || fraction > 1 {
+		return nil, nil, fmt.Errorf("fraction %v must be in (0, 1]", fraction)
+	}
+	deadline, ok := parent.Deadline()
+	if !ok {
+		return nil, nil, ErrNoDeadline
+	}
+
+	remaining := time.Until(deadline)
+	ctx, cancel := context.WithTimeout(parent, time.Duration(float64(remaining)*fraction))
+	return ctx, cancel, nil
+}
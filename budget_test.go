@@ -0,0 +1,35 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBudgetedContext(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ctx, cancelChild, err := BudgetedContext(parent, 0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelChild()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("expected the child to have a deadline")
+	}
+	remaining := time.Until(deadline)
+	if remaining > 2500*time.Millisecond || remaining < 2400*time.Millisecond {
+		t.Fatalf("expected about 2.5s, got %s", remaining)
+	}
+}
+
+func TestBudgetedContextWithoutDeadline(t *testing.T) {
+	_, _, err := BudgetedContext(context.Background(), 0.5)
+	if !errors.Is(err, ErrNoDeadline) {
+		t.Fatalf("expected ErrNoDeadline, got %v", err)
+	}
+}
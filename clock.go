@@ -0,0 +1,15 @@
+package belajargolangdatabase
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+var RealClock Clock = realClock{}
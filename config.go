@@ -0,0 +1,208 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// nama tabel tanpa prefix, lihat Config.Table
+const (
+	CustomerTable        = "customer"
+	CustomerArchiveTable = "customer_archive"
+	UserTable            = "user"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+type Config struct {
+	User              string
+	Password          string
+	Host              string
+	Port              int
+	Database          string
+	Charset           string
+	Collation         string
+	Location          *time.Location // timestamps are stored in UTC and converted to Location on read
+	MaxRows           int            // caps list queries, 0 means unlimited
+	Clock             Clock          // fills created_at, defaults to RealClock
+	MaxExecutionTime  time.Duration  // MySQL only, the server aborts SELECTs running longer than this, rounded up to 1ms
+	EmptyStringAsNull bool           // store empty nullable strings such as email as NULL
+	NormalizeNames    bool           // trim, collapse spaces and title case names on insert and update
+	TablePrefix       string         // prepended to the customer, customer_archive and user table names
+	ConnLogger        *log.Logger    // logs every physical connection opened and closed
+	SessionInit       []string       // run on every new connection, e.g. SET SESSION ...
+	InterpolateParams bool           // no server side prepare, the driver escapes and inlines the args itself
+	MaxIdleConns      int
+	MaxOpenConns      int
+	ConnMaxIdleTime   time.Duration
+	ConnMaxLifetime   time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		User:            "root",
+		Host:            "localhost",
+		Port:            3306,
+		Database:        "belajar_golang_database",
+		Charset:         "utf8mb4",
+		Collation:       "utf8mb4_unicode_ci",
+		MaxIdleConns:    10,
+		MaxOpenConns:    100,
+		ConnMaxIdleTime: 5 * time.Minute,
+		ConnMaxLifetime: 60 * time.Minute,
+	}
+}
+
+func (config Config) Address() string {
+	return net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+}
+
+// Table returns name with TablePrefix applied
+func (config Config) Table(name string) string {
+	return config.TablePrefix + name
+}
+
+// QuotedTable is Table quoted with backticks, ready to be put into a statement.
+// A backtick inside the name is doubled, although ValidateConfig rejects those prefixes.
+func (config Config) QuotedTable(name string) string {
+	return "`" + strings.ReplaceAll(config.Table(name), "`", "``") + "`"
+}
+
+// prefix harus lolos tableNamePattern yang sama dengan DescribeTable
+func (config Config) validateTablePrefix() error {
+	if config.TablePrefix != "" && !tableNamePattern.MatchString(config.TablePrefix) {
+		return fmt.Errorf("%w: TablePrefix %q must start with a letter or underscore followed by letters, digits and underscores",
+			ErrInvalidConfig, config.TablePrefix)
+	}
+	return nil
+}
+
+func (config Config) DSN() string {
+	mysqlConfig := mysql.NewConfig()
+	mysqlConfig.User = config.User
+	mysqlConfig.Passwd = config.Password
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = config.Address()
+	mysqlConfig.DBName = config.Database
+	mysqlConfig.ParseTime = true
+	mysqlConfig.Loc = time.UTC
+	mysqlConfig.InterpolateParams = config.InterpolateParams
+
+	// utf8mb4 supaya nama dengan emoji / karakter multibyte tidak rusak
+	charset, collation := config.Charset, config.Collation
+	if charset == "" {
+		charset = "utf8mb4"
+	}
+	if collation == "" {
+		collation = "utf8mb4_unicode_ci"
+	}
+	mysqlConfig.Params = map[string]string{"charset": charset, "time_zone": "'+00:00'"}
+	mysqlConfig.Collation = collation
+	return mysqlConfig.FormatDSN()
+}
+
+// ValidateConfig rejects a TablePrefix that is not a plain identifier and pool
+// settings that database/sql would silently adjust or ignore
+func ValidateConfig(config Config) error {
+	if err := config.validateTablePrefix(); err != nil {
+		return err
+	}
+	switch {
+	case config.MaxIdleConns < 0 || config.MaxOpenConns < 0:
+		return fmt.Errorf("%w: MaxIdleConns and MaxOpenConns must not be negative", ErrInvalidConfig)
+	case config.MaxOpenConns == 0 && config.MaxIdleConns > 0:
+		return fmt.Errorf("%w: MaxOpenConns is 0 (unlimited) while MaxIdleConns is %d, set MaxOpenConns explicitly",
+			ErrInvalidConfig, config.MaxIdleConns)
+	case config.MaxIdleConns > config.MaxOpenConns:
+		// database/sql diam-diam menurunkan idle menjadi sama dengan open
+		return fmt.Errorf("%w: MaxIdleConns %d is above MaxOpenConns %d and would be capped to it, lower MaxIdleConns",
+			ErrInvalidConfig, config.MaxIdleConns, config.MaxOpenConns)
+	case config.MaxExecutionTime < 0:
+		return fmt.Errorf("%w: MaxExecutionTime must not be negative, use 0 for no limit", ErrInvalidConfig)
+	case config.ConnMaxLifetime < 0 || config.ConnMaxIdleTime < 0:
+		return fmt.Errorf("%w: ConnMaxLifetime and ConnMaxIdleTime must not be negative, use 0 to keep connections forever",
+			ErrInvalidConfig)
+	}
+	return nil
+}
+
+// sessionStatements returns SessionInit followed by the statements derived from
+// the other options, run by initConnector on every new connection
+func (config Config) sessionStatements() []string {
+	statements := append([]string{}, config.SessionInit...)
+	if config.MaxExecutionTime > 0 {
+		// dibulatkan ke atas, MAX_EXECUTION_TIME = 0 berarti tanpa batas
+		milliseconds := (config.MaxExecutionTime + time.Millisecond - 1) / time.Millisecond
+		statements = append(statements, "SET SESSION MAX_EXECUTION_TIME = "+strconv.FormatInt(int64(milliseconds), 10))
+	}
+	return statements
+}
+
+func open(config Config) (*sql.DB, error) {
+	mysqlConfig, err := mysql.ParseDSN(config.DSN())
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return nil, err
+	}
+	var wrapped driver.Connector = &initConnector{Connector: connector, statements: config.sessionStatements()}
+	if config.ConnLogger != nil {
+		wrapped = &loggingConnector{Connector: wrapped, logger: config.ConnLogger}
+	}
+	db := sql.OpenDB(wrapped)
+
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	return db, nil
+}
+
+func GetConnectionWithConfig(config Config) *sql.DB {
+	err := ValidateConfig(config)
+	if err != nil {
+		panic(err)
+	}
+	db, err := open(config)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// Connect opens the pool and pings it, so an unreachable database fails at startup
+func Connect(ctx context.Context, config Config) (*sql.DB, error) {
+	err := ValidateDSN(config.DSN())
+	if err != nil {
+		return nil, err
+	}
+	err = ValidateConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := open(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot reach database at %s: %w", config.Address(), err)
+	}
+	return db, nil
+}
@@ -0,0 +1,190 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestConfigDSN(t *testing.T) {
+	dsn := DefaultConfig().DSN()
+	expected := "root@tcp(localhost:3306)/belajar_golang_database?collation=utf8mb4_unicode_ci&parseTime=true&charset=utf8mb4&time_zone=%27%2B00%3A00%27"
+	if dsn != expected {
+		t.Fatalf("expected %s, got %s", expected, dsn)
+	}
+}
+
+func TestConfigDSNDefaultCharset(t *testing.T) {
+	config := DefaultConfig()
+	config.Charset = ""
+	config.Collation = ""
+	if dsn := config.DSN(); !strings.Contains(dsn, "charset=utf8mb4") || !strings.Contains(dsn, "collation=utf8mb4_unicode_ci") {
+		t.Fatalf("expected utf8mb4 defaults, got %s", dsn)
+	}
+
+	config.Charset = "latin1"
+	config.Collation = "latin1_swedish_ci"
+	if dsn := config.DSN(); !strings.Contains(dsn, "charset=latin1") || !strings.Contains(dsn, "collation=latin1_swedish_ci") {
+		t.Fatalf("expected custom charset, got %s", dsn)
+	}
+}
+
+func TestConnectUnreachable(t *testing.T) {
+	config := DefaultConfig()
+	config.Host = "10.255.255.1"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	db, err := Connect(ctx, config)
+	if err == nil {
+		db.Close()
+		t.Fatal("expected connect to fail")
+	}
+	if !strings.Contains(err.Error(), "cannot reach database at 10.255.255.1:3306") {
+		t.Fatalf("expected descriptive error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("connect did not honor the context deadline, took %s", elapsed)
+	}
+}
+
+func TestConfigMaxExecutionTime(t *testing.T) {
+	config := DefaultConfig()
+	if statements := config.sessionStatements(); len(statements) != 0 {
+		t.Fatalf("expected no session statements by default, got %v", statements)
+	}
+
+	config.SessionInit = []string{"SET SESSION sql_mode = 'STRICT_ALL_TABLES'"}
+	config.MaxExecutionTime = 500 * time.Millisecond
+	statements := config.sessionStatements()
+	if len(statements) != 2 || statements[0] != config.SessionInit[0] || statements[1] != "SET SESSION MAX_EXECUTION_TIME = 500" {
+		t.Fatalf("expected SessionInit then MAX_EXECUTION_TIME = 500, got %v", statements)
+	}
+	if strings.Contains(config.DSN(), "max_execution_time") {
+		t.Fatal("expected max_execution_time to be set by the session init, not the DSN")
+	}
+
+	// di bawah 1ms tidak boleh menjadi 0 yang berarti tanpa batas
+	config.MaxExecutionTime = 500 * time.Microsecond
+	if statements := config.sessionStatements(); statements[1] != "SET SESSION MAX_EXECUTION_TIME = 1" {
+		t.Fatalf("expected the limit to round up to 1ms, got %v", statements)
+	}
+}
+
+func TestMaxExecutionTimeAbortsSelect(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxExecutionTime = 500 * time.Millisecond
+
+	db, err := Connect(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	start := time.Now()
+	var interrupted int
+	err = db.QueryRow("SELECT SLEEP(2)").Scan(&interrupted)
+	elapsed := time.Since(start)
+
+	// MySQL either fails with 3024 or stops SLEEP early and returns 1
+	var mysqlErr *mysql.MySQLError
+	if err != nil && !(errors.As(err, &mysqlErr) && mysqlErr.Number == 3024) {
+		t.Fatal(err)
+	}
+	if err == nil && interrupted != 1 {
+		t.Fatal("expected the server to interrupt SLEEP(2)")
+	}
+	if elapsed > 1500*time.Millisecond {
+		t.Fatalf("expected the server to abort after 500ms, took %s", elapsed)
+	}
+}
+
+func TestConfigInterpolateParams(t *testing.T) {
+	config := DefaultConfig()
+	if strings.Contains(config.DSN(), "interpolateParams") {
+		t.Fatal("expected prepared statements by default")
+	}
+
+	config.InterpolateParams = true
+	if dsn := config.DSN(); !strings.Contains(dsn, "interpolateParams=true") {
+		t.Fatalf("expected interpolateParams=true, got %s", dsn)
+	}
+}
+
+func TestInterpolateParamsEscapesInput(t *testing.T) {
+	setup, _ := SetupTestDB(t)
+	_, err := setup.Exec("INSERT INTO user(username, password) VALUES (?, ?)", "admin", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig()
+	config.InterpolateParams = true
+	db, err := Connect(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var username string
+	script := "SELECT username FROM user WHERE username = ? AND password = ? LIMIT 1"
+	err = db.QueryRow(script, "admin'; #", "salah").Scan(&username)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected the malicious input to match nothing, got %q, %v", username, err)
+	}
+	err = db.QueryRow(script, "admin", "' OR '1'='1").Scan(&username)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected the malicious input to match nothing, got %q, %v", username, err)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	if err := ValidateConfig(DefaultConfig()); err != nil {
+		t.Fatalf("expected the default config to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		change   func(config *Config)
+		expected string
+	}{
+		{"idle above open", func(config *Config) { config.MaxIdleConns, config.MaxOpenConns = 20, 10 }, "MaxIdleConns 20 is above MaxOpenConns 10"},
+		{"unlimited open with idle", func(config *Config) { config.MaxOpenConns = 0 }, "MaxOpenConns is 0 (unlimited)"},
+		{"negative idle", func(config *Config) { config.MaxIdleConns = -1 }, "must not be negative"},
+		{"negative lifetime", func(config *Config) { config.ConnMaxLifetime = -time.Minute }, "ConnMaxLifetime and ConnMaxIdleTime"},
+		{"negative idle time", func(config *Config) { config.ConnMaxIdleTime = -time.Minute }, "ConnMaxLifetime and ConnMaxIdleTime"},
+		{"prefix with backtick", func(config *Config) { config.TablePrefix = "app`; DROP TABLE customer; -- " }, "TablePrefix"},
+		{"prefix with dash", func(config *Config) { config.TablePrefix = "app-" }, "TablePrefix"},
+		{"negative execution time", func(config *Config) { config.MaxExecutionTime = -time.Second }, "MaxExecutionTime must not be negative"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := DefaultConfig()
+			test.change(&config)
+			err := ValidateConfig(config)
+			if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), test.expected) {
+				t.Fatalf("expected ErrInvalidConfig mentioning %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestConfigQuotedTable(t *testing.T) {
+	config := DefaultConfig()
+	config.TablePrefix = "app_"
+	if table := config.QuotedTable(CustomerTable); table != "`app_customer`" {
+		t.Fatalf("expected `app_customer`, got %s", table)
+	}
+
+	config.TablePrefix = "a`b_"
+	if table := config.QuotedTable(CustomerTable); table != "`a``b_customer`" {
+		t.Fatalf("expected the backtick to be doubled, got %s", table)
+	}
+}
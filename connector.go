@@ -0,0 +1,125 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"sync/atomic"
+)
+
+// initConnector runs statements on every new connection with the context of the
+// caller that needed it, so a slow init is cut off by the caller's deadline
+type initConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func (connector *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := connector.Connector.Connect(ctx)
+	if err != nil || len(connector.statements) == 0 {
+		return conn, err
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("driver connection does not support ExecContext")
+	}
+	for _, statement := range connector.statements {
+		_, err := execer.ExecContext(ctx, statement, nil)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// loggingConnector logs every physical connection opened and closed, numbered
+// in opening order, to show churn caused by a short ConnMaxLifetime
+type loggingConnector struct {
+	driver.Connector
+	logger *log.Logger
+	next   atomic.Int64
+}
+
+func (connector *loggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := connector.Connector.Connect(ctx)
+	if err != nil {
+		connector.logger.Printf("connection failed: %v", err)
+		return nil, err
+	}
+	number := connector.next.Add(1)
+	connector.logger.Printf("connection #%d opened", number)
+	return &loggingConn{Conn: conn, logger: connector.logger, number: number}, nil
+}
+
+// loggingConn forwards the optional driver interfaces so wrapping the connection
+// does not make database/sql fall back to slower paths
+type loggingConn struct {
+	driver.Conn
+	logger *log.Logger
+	number int64
+}
+
+func (conn *loggingConn) Close() error {
+	conn.logger.Printf("connection #%d closed", conn.number)
+	return conn.Conn.Close()
+}
+
+func (conn *loggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := conn.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (conn *loggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := conn.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (conn *loggingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := conn.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return conn.Conn.Prepare(query)
+}
+
+func (conn *loggingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := conn.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return conn.Conn.Begin()
+}
+
+func (conn *loggingConn) Ping(ctx context.Context) error {
+	if pinger, ok := conn.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (conn *loggingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := conn.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (conn *loggingConn) IsValid() bool {
+	if validator, ok := conn.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (conn *loggingConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := conn.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
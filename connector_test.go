@@ -0,0 +1,120 @@
+package belajargolangdatabase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+type slowConn struct {
+	closed bool
+}
+
+func (conn *slowConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (conn *slowConn) Close() error {
+	conn.closed = true
+	return nil
+}
+
+func (conn *slowConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+// ExecContext meniru SET SESSION yang lambat, hanya berhenti saat context selesai
+func (conn *slowConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type slowConnector struct {
+	conn *slowConn
+}
+
+func (connector *slowConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return connector.conn, nil
+}
+
+func (connector *slowConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestInitConnectorHonorsDeadline(t *testing.T) {
+	conn := &slowConn{}
+	db := sql.OpenDB(&initConnector{Connector: &slowConnector{conn: conn}, statements: []string{"SET SESSION wait_timeout = 60"}})
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := db.PingContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("init kept running past the deadline, took %s", time.Since(start))
+	}
+	if !conn.closed {
+		t.Fatal("expected the half initialised connection to be closed")
+	}
+}
+
+func TestConnectSlowSessionInit(t *testing.T) {
+	config := DefaultConfig()
+	config.SessionInit = []string{"DO SLEEP(5)"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	db, err := Connect(ctx, config)
+	if err == nil {
+		db.Close()
+		t.Fatal("expected Connect to fail while the session init is still running")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatalf("Connect blocked past the deadline, took %s", time.Since(start))
+	}
+}
+
+func TestConnLoggerLogsReconnect(t *testing.T) {
+	var output bytes.Buffer
+	config := DefaultConfig()
+	config.ConnLogger = log.New(&output, "", 0)
+	config.MaxOpenConns = 1
+	config.MaxIdleConns = 1
+	config.ConnMaxLifetime = 100 * time.Millisecond
+
+	db, err := Connect(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	err = db.Ping()
+	// ditutup dulu supaya tidak ada goroutine pool yang masih menulis log saat dibaca
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opened := strings.Count(output.String(), "opened")
+	if opened < 2 {
+		t.Fatalf("expected the expired connection to be replaced, log:\n%s", output.String())
+	}
+	if !strings.Contains(output.String(), "connection #1 closed") || !strings.Contains(output.String(), "connection #2 opened") {
+		t.Fatalf("expected numbered open and close events, log:\n%s", output.String())
+	}
+}
@@ -2,19 +2,8 @@ package belajargolangdatabase
 
 import (
 	"database/sql"
-	"time"
 )
 
 func GetConnection() *sql.DB {
-	db, err := sql.Open("mysql", "root:@tcp(localhost:3306)/belajar_golang_database?parseTime=true")
-	if err != nil {
-		panic(err)
-	}
-
-	db.SetMaxIdleConns(10)
-	db.SetMaxOpenConns(100)
-	db.SetConnMaxIdleTime(5 * time.Minute)
-	db.SetConnMaxLifetime(60 * time.Minute)
-
-	return db
+	return GetConnectionWithConfig(DefaultConfig())
 }
@@ -14,7 +14,7 @@ func TestEmpty(t *testing.T) {
 func TestOpenConnection(t *testing.T) {
 	db, err := sql.Open("mysql", "root:@tcp(localhost:3306)/belajar_golang_database")
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	defer db.Close()
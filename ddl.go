@@ -0,0 +1,20 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ExecDDL runs statements in order and stops at the first failure. MySQL commits
+// implicitly around DDL, so each statement is applied on its own and the ones
+// before a failure are not rolled back.
+func ExecDDL(ctx context.Context, db *sql.DB, statements ...string) error {
+	for i, statement := range statements {
+		_, err := db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("statement %d (%s): %w", i, statement, err)
+		}
+	}
+	return nil
+}
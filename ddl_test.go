@@ -0,0 +1,62 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func dropDDLTestTable(t *testing.T, db *sql.DB) {
+	db.Exec("DROP TABLE IF EXISTS ddl_test")
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS ddl_test")
+	})
+}
+
+func TestExecDDL(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	dropDDLTestTable(t, db)
+	ctx := context.Background()
+
+	err := ExecDDL(ctx, db,
+		"CREATE TABLE ddl_test (id INT NOT NULL, code VARCHAR(20), PRIMARY KEY (id))",
+		"CREATE INDEX idx_ddl_test_code ON ddl_test (code)",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := DescribeTable(ctx, db, "ddl_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(columns) != 2 {
+		t.Fatalf("expected 2 columns, got %+v", columns)
+	}
+
+	var total int
+	script := "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'ddl_test' AND index_name = 'idx_ddl_test_code'"
+	if err := db.QueryRowContext(ctx, script).Scan(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total == 0 {
+		t.Fatal("expected idx_ddl_test_code to be created")
+	}
+}
+
+func TestExecDDLNamesFailingStatement(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	dropDDLTestTable(t, db)
+
+	err := ExecDDL(context.Background(), db,
+		"CREATE TABLE ddl_test (id INT NOT NULL, PRIMARY KEY (id))",
+		"CREATE INDEX ON ddl_test",
+	)
+	if err == nil {
+		t.Fatal("expected invalid statement to fail")
+	}
+	if !strings.Contains(err.Error(), "statement 1") || !strings.Contains(err.Error(), "CREATE INDEX ON ddl_test") {
+		t.Fatalf("expected error to name the failing statement, got %v", err)
+	}
+}
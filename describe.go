@@ -0,0 +1,51 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type ColumnInfo struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Default  sql.NullString
+}
+
+// DescribeTable lists the columns of table in the current database, in table order
+func DescribeTable(ctx context.Context, db *sql.DB, table string) ([]ColumnInfo, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	script := "SELECT column_name, column_type, is_nullable, column_default FROM information_schema.columns " +
+		"WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position"
+	rows, err := db.QueryContext(ctx, script, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var column ColumnInfo
+		var nullable string
+		err := rows.Scan(&column.Name, &column.Type, &nullable, &column.Default)
+		if err != nil {
+			return nil, err
+		}
+		column.Nullable = nullable == "YES"
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %q not found", table)
+	}
+	return columns, nil
+}
@@ -0,0 +1,46 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDescribeCustomerTable(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	columns, err := DescribeTable(context.Background(), db, "customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := map[string]ColumnInfo{}
+	for _, column := range columns {
+		byName[column.Name] = column
+	}
+	for _, name := range []string{"id", "name", "email", "balance", "rating", "created_at", "birth_date", "married", "status"} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("expected column %s, got %+v", name, columns)
+		}
+	}
+	if columns[0].Name != "id" {
+		t.Fatalf("expected columns in table order, got %s first", columns[0].Name)
+	}
+	if byName["id"].Nullable || byName["name"].Nullable {
+		t.Fatal("expected id and name to be NOT NULL")
+	}
+	if !byName["email"].Nullable || !byName["birth_date"].Nullable {
+		t.Fatal("expected email and birth_date to be nullable")
+	}
+	if !byName["status"].Default.Valid || byName["status"].Default.String != "active" {
+		t.Fatalf("expected status default active, got %+v", byName["status"].Default)
+	}
+}
+
+func TestDescribeTableRejectsInvalidName(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	_, err := DescribeTable(context.Background(), db, "customer; DROP TABLE customer")
+	if err == nil {
+		t.Fatal("expected invalid table name to be rejected")
+	}
+}
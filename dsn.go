@@ -0,0 +1,32 @@
+package belajargolangdatabase
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+var ErrInvalidDSN = errors.New("invalid DSN")
+
+// ValidateDSN checks a MySQL DSN before opening a connection with it
+func ValidateDSN(dsn string) error {
+	mysqlConfig, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDSN, err)
+	}
+
+	// ParseDSN mengisi host default, jadi host yang kosong dicek dari DSN aslinya
+	if mysqlConfig.Net == "tcp" {
+		host, _, err := net.SplitHostPort(mysqlConfig.Addr)
+		if err != nil || host == "" || !strings.Contains(dsn, "tcp(") || strings.Contains(dsn, "tcp()") {
+			return fmt.Errorf("%w: missing host, expected user:password@tcp(host:port)/database", ErrInvalidDSN)
+		}
+	}
+	if mysqlConfig.DBName == "" {
+		return fmt.Errorf("%w: missing database name after the slash", ErrInvalidDSN)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package belajargolangdatabase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateDSN(t *testing.T) {
+	err := ValidateDSN("root:@tcp(localhost:3306)/belajar_golang_database?parseTime=true")
+	if err != nil {
+		t.Fatalf("expected valid DSN, got %v", err)
+	}
+	if err := ValidateDSN(DefaultConfig().DSN()); err != nil {
+		t.Fatalf("expected the default config DSN to be valid, got %v", err)
+	}
+}
+
+func TestValidateDSNMissingDatabase(t *testing.T) {
+	err := ValidateDSN("root:@tcp(localhost:3306)/")
+	if !errors.Is(err, ErrInvalidDSN) || !strings.Contains(err.Error(), "missing database name") {
+		t.Fatalf("expected missing database error, got %v", err)
+	}
+}
+
+func TestValidateDSNMissingHost(t *testing.T) {
+	for _, dsn := range []string{"root:@tcp()/belajar_golang_database", "root:@/belajar_golang_database", "root:@tcp(:3306)/belajar_golang_database"} {
+		err := ValidateDSN(dsn)
+		if !errors.Is(err, ErrInvalidDSN) || !strings.Contains(err.Error(), "missing host") {
+			t.Fatalf("%s: expected missing host error, got %v", dsn, err)
+		}
+	}
+}
+
+func TestValidateDSNGarbage(t *testing.T) {
+	err := ValidateDSN("ini bukan dsn")
+	if !errors.Is(err, ErrInvalidDSN) {
+		t.Fatalf("expected ErrInvalidDSN, got %v", err)
+	}
+}
+
+func TestConnectRejectsInvalidDSN(t *testing.T) {
+	config := DefaultConfig()
+	config.Database = ""
+
+	_, err := Connect(t.Context(), config)
+	if !errors.Is(err, ErrInvalidDSN) {
+		t.Fatalf("expected Connect to fail the pre-flight check, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type BalanceEvent struct {
+	Id          int32
+	CustomerId  string
+	Amount      int32
+	Description sql.NullString
+	CreatedAt   time.Time
+	Balance     int64 // saldo berjalan setelah event ini
+}
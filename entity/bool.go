@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// Bool normalizes tinyint(1) columns that the driver may return as
+// bool, an integer, or []byte depending on its settings
+type Bool bool
+
+func (b *Bool) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*b = false
+	case bool:
+		*b = Bool(v)
+	case int64:
+		*b = v != 0
+	case int32:
+		*b = v != 0
+	case int:
+		*b = v != 0
+	case uint8:
+		*b = v != 0
+	case []byte:
+		return b.parse(string(v))
+	case string:
+		return b.parse(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Bool", value)
+	}
+	return nil
+}
+
+func (b *Bool) parse(value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into Bool", value)
+	}
+	*b = Bool(parsed)
+	return nil
+}
+
+func (b Bool) Value() (driver.Value, error) {
+	return bool(b), nil
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "testing"
+
+func TestBoolScan(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected Bool
+	}{
+		{true, true},
+		{false, false},
+		{int64(1), true},
+		{int64(0), false},
+		{[]byte("1"), true},
+		{[]byte("0"), false},
+		{"true", true},
+		{"false", false},
+		{nil, false},
+	}
+
+	for _, test := range tests {
+		b := Bool(!test.expected)
+		err := b.Scan(test.value)
+		if err != nil {
+			t.Fatalf("scan %#v: %v", test.value, err)
+		}
+		if b != test.expected {
+			t.Fatalf("scan %#v: expected %v, got %v", test.value, test.expected, b)
+		}
+	}
+}
+
+func TestBoolScanInvalid(t *testing.T) {
+	var b Bool
+	if err := b.Scan("mungkin"); err == nil {
+		t.Fatal("expected error for unparseable value")
+	}
+	if err := b.Scan(3.14); err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Customer struct {
+	Id        string
+	Name      string
+	Email     sql.NullString
+	Balance   int32
+	Rating    float64
+	CreatedAt time.Time
+	BirthDate sql.NullTime
+	Married   Bool
+	Status    Status
+}
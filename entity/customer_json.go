@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// customerJSON writes nullable columns as JSON null instead of {"String":"","Valid":false}
+type customerJSON struct {
+	Id        string     `json:"id"`
+	Name      string     `json:"name"`
+	Email     *string    `json:"email"`
+	Balance   int32      `json:"balance"`
+	Rating    float64    `json:"rating"`
+	CreatedAt time.Time  `json:"created_at"`
+	BirthDate *time.Time `json:"birth_date"`
+	Married   bool       `json:"married"`
+	Status    Status     `json:"status"`
+}
+
+func (customer Customer) MarshalJSON() ([]byte, error) {
+	value := customerJSON{
+		Id:        customer.Id,
+		Name:      customer.Name,
+		Balance:   customer.Balance,
+		Rating:    customer.Rating,
+		CreatedAt: customer.CreatedAt,
+		Married:   bool(customer.Married),
+		Status:    customer.Status,
+	}
+	if customer.Email.Valid {
+		value.Email = &customer.Email.String
+	}
+	if customer.BirthDate.Valid {
+		value.BirthDate = &customer.BirthDate.Time
+	}
+	return json.Marshal(value)
+}
+
+func (customer *Customer) UnmarshalJSON(data []byte) error {
+	value := customerJSON{}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	*customer = Customer{
+		Id:        value.Id,
+		Name:      value.Name,
+		Balance:   value.Balance,
+		Rating:    value.Rating,
+		CreatedAt: value.CreatedAt,
+		Married:   Bool(value.Married),
+		Status:    value.Status,
+	}
+	if value.Email != nil {
+		customer.Email = sql.NullString{String: *value.Email, Valid: true}
+	}
+	if value.BirthDate != nil {
+		customer.BirthDate = sql.NullTime{Time: *value.BirthDate, Valid: true}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCustomerJsonNull(t *testing.T) {
+	bytes, err := json.Marshal(Customer{Id: "nafis", Name: "Nafis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bytes), `"email":null`) || !strings.Contains(string(bytes), `"birth_date":null`) {
+		t.Fatalf("expected null email and birth_date, got %s", bytes)
+	}
+}
+
+func TestCustomerJsonRoundTrip(t *testing.T) {
+	customer := Customer{Id: "nafis", Name: "Nafis", Email: sql.NullString{String: "nafis@test.com", Valid: true}, Married: true}
+
+	bytes, err := json.Marshal(customer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := Customer{}
+	err = json.Unmarshal(bytes, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Id != "nafis" || decoded.Email != customer.Email || !bool(decoded.Married) || decoded.BirthDate.Valid {
+		t.Fatalf("expected %+v, got %+v", customer, decoded)
+	}
+}
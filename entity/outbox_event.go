@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OutboxEvent struct {
+	Id          int64
+	Topic       string
+	Payload     string
+	CreatedAt   time.Time
+	PublishedAt sql.NullTime
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusInactive Status = "inactive"
+	StatusBanned   Status = "banned"
+)
+
+func ParseStatus(value string) (Status, error) {
+	switch status := Status(value); status {
+	case StatusActive, StatusInactive, StatusBanned:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown customer status %q", value)
+	}
+}
+
+func (s *Status) Scan(value any) error {
+	var raw string
+	switch v := value.(type) {
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into Status", value)
+	}
+
+	status, err := ParseStatus(raw)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+func (s Status) Value() (driver.Value, error) {
+	status, err := ParseStatus(string(s))
+	if err != nil {
+		return nil, err
+	}
+	return string(status), nil
+}
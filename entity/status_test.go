@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatusScan(t *testing.T) {
+	for _, value := range []any{"active", []byte("inactive"), "banned"} {
+		var status Status
+		err := status.Scan(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseStatus(string(status)); err != nil {
+			t.Fatalf("scan %v produced invalid status %q", value, status)
+		}
+	}
+}
+
+func TestStatusScanUnknown(t *testing.T) {
+	var status Status
+	err := status.Scan("deleted")
+	if err == nil || !strings.Contains(err.Error(), `unknown customer status "deleted"`) {
+		t.Fatalf("expected unknown status error, got %v", err)
+	}
+}
+
+func TestStatusValueUnknown(t *testing.T) {
+	if _, err := Status("deleted").Value(); err == nil {
+		t.Fatal("expected unknown status to be rejected on write")
+	}
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+type User struct {
+	Id           int32
+	Username     string
+	PasswordHash string `json:"-"`
+	CreatedAt    time.Time
+}
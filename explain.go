@@ -0,0 +1,70 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+)
+
+type ExplainRow map[string]string
+
+func Explain(ctx context.Context, db *sql.DB, query string, args ...any) ([]ExplainRow, error) {
+	rows, err := db.QueryContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ExplainRow
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		err := rows.Scan(pointers...)
+		if err != nil {
+			return nil, err
+		}
+
+		row := ExplainRow{}
+		for i, column := range columns {
+			row[column] = values[i].String
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// WarnIfFullScan reports whether MySQL plans a full table scan (type ALL) for query
+func WarnIfFullScan(ctx context.Context, db *sql.DB, query string, args ...any) (bool, error) {
+	rows, err := Explain(ctx, db, query, args...)
+	if err != nil {
+		return false, err
+	}
+	for _, row := range rows {
+		if row["type"] == "ALL" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AssertUsesIndex fails t when MySQL plans a full table scan for query
+func AssertUsesIndex(t TestReporter, db *sql.DB, query string, args ...any) {
+	t.Helper()
+	rows, err := Explain(context.Background(), db, query, args...)
+	if err != nil {
+		t.Errorf("explain %s: %v", query, err)
+		return
+	}
+	for _, row := range rows {
+		if row["type"] == "ALL" {
+			t.Errorf("full table scan on %s for %s", row["table"], query)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestWarnIfFullScan(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		_, err := db.ExecContext(ctx, "INSERT INTO customer(id, name) VALUES(?, ?)", "customer"+strconv.Itoa(i), "Customer "+strconv.Itoa(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fullScan, err := WarnIfFullScan(ctx, db, "SELECT id FROM customer WHERE name = ?", "Customer 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fullScan {
+		t.Fatal("expected a full table scan on the unindexed name column")
+	}
+
+	fullScan, err = WarnIfFullScan(ctx, db, "SELECT name FROM customer WHERE id = ?", "customer5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullScan {
+		t.Fatal("expected primary key lookup to use the index")
+	}
+}
+
+func TestAssertUsesIndex(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	for i := 0; i < 20; i++ {
+		_, err := db.Exec("INSERT INTO customer(id, name, email) VALUES(?, ?, ?)", "customer"+strconv.Itoa(i), "Customer "+strconv.Itoa(i), "customer"+strconv.Itoa(i)+"@test.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reporter := &fakeReporter{}
+	AssertUsesIndex(reporter, db, "SELECT name FROM customer WHERE id = ?", "customer5")
+	if len(reporter.errors) != 0 {
+		t.Fatalf("expected primary key lookup to pass, got %v", reporter.errors)
+	}
+
+	reporter = &fakeReporter{}
+	AssertUsesIndex(reporter, db, "SELECT id FROM customer WHERE name = ?", "Customer 5")
+	if len(reporter.errors) == 0 {
+		t.Fatal("expected scan on unindexed name to fail")
+	}
+}
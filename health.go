@@ -0,0 +1,32 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+const readinessTimeout = 2 * time.Second
+
+// LivenessHandler answers 200 as long as the process is running, it never touches the database
+func LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// ReadinessHandler answers 503 until the database can be pinged
+func ReadinessHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+
+		err := db.PingContext(ctx)
+		if err != nil {
+			http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
@@ -0,0 +1,39 @@
+package belajargolangdatabase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLivenessHandlerWithClosedDB(t *testing.T) {
+	db := GetConnection()
+	db.Close()
+
+	recorder := httptest.NewRecorder()
+	LivenessHandler(recorder, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+}
+
+func TestReadinessHandlerWithClosedDB(t *testing.T) {
+	db := GetConnection()
+	db.Close()
+
+	recorder := httptest.NewRecorder()
+	ReadinessHandler(db)(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", recorder.Code)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	recorder := httptest.NewRecorder()
+	ReadinessHandler(db)(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,40 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+)
+
+// OptimizeCustomerTable rebuilds the customer table. MySQL reports the outcome
+// as rows: errors are returned, notes and warnings are logged.
+func OptimizeCustomerTable(ctx context.Context, db *sql.DB) error {
+	return OptimizeCustomerTableWithConfig(ctx, db, DefaultConfig())
+}
+
+// OptimizeCustomerTableWithConfig is OptimizeCustomerTable for the customer table named with config.TablePrefix
+func OptimizeCustomerTableWithConfig(ctx context.Context, db *sql.DB, config Config) error {
+	rows, err := db.QueryContext(ctx, "OPTIMIZE TABLE "+config.QuotedTable(CustomerTable))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, op, msgType, msgText string
+		err := rows.Scan(&table, &op, &msgType, &msgText)
+		if err != nil {
+			return err
+		}
+
+		switch msgType {
+		case "error":
+			return errors.New(table + ": " + msgText)
+		case "status":
+		default:
+			log.Println(table, msgType+":", msgText)
+		}
+	}
+	return rows.Err()
+}
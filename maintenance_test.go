@@ -0,0 +1,28 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestOptimizeCustomerTable(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	ctx := context.Background()
+
+	for i := 0; i < 100; i++ {
+		_, err := db.ExecContext(ctx, "INSERT INTO customer(id, name) VALUES(?, ?)", "customer"+strconv.Itoa(i), "Customer")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := db.ExecContext(ctx, "DELETE FROM customer WHERE id LIKE 'customer%'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = OptimizeCustomerTable(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,199 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+)
+
+// migrations returns the CREATE TABLE statements with config.TablePrefix applied
+func migrations(config Config) []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS comments (
+			id INT NOT NULL AUTO_INCREMENT,
+			email VARCHAR(100) NOT NULL,
+			comment TEXT,
+			PRIMARY KEY (id)
+		)`,
+		"CREATE TABLE IF NOT EXISTS " + config.QuotedTable(CustomerTable) + ` (
+			id VARCHAR(100) NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			email VARCHAR(100),
+			balance INT DEFAULT 0,
+			rating DOUBLE DEFAULT 0.0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			birth_date DATE,
+			married BOOLEAN DEFAULT false,
+			status VARCHAR(20) NOT NULL DEFAULT 'active',
+			deleted_at TIMESTAMP NULL,
+			updated_at TIMESTAMP(6) NULL,
+			PRIMARY KEY (id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS balance_audit (
+			id INT NOT NULL AUTO_INCREMENT,
+			customer_id VARCHAR(100) NOT NULL,
+			amount INT NOT NULL,
+			description VARCHAR(100),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id)
+		)`,
+		"CREATE TABLE IF NOT EXISTS idempotency_keys (" +
+			"`key` VARCHAR(100) NOT NULL," +
+			"created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`key`)" +
+			")",
+		"CREATE TABLE IF NOT EXISTS " + config.QuotedTable(UserTable) + ` (
+			id INT NOT NULL AUTO_INCREMENT,
+			username VARCHAR(100) NOT NULL,
+			password VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			UNIQUE KEY (username)
+		)`,
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id BIGINT NOT NULL AUTO_INCREMENT,
+			topic VARCHAR(100) NOT NULL,
+			payload TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			published_at TIMESTAMP NULL,
+			PRIMARY KEY (id)
+		)`,
+	}
+}
+
+// kolom yang ditambahkan setelah tabelnya dibuat, untuk database yang sudah ada.
+// Kolom customer juga ditambahkan ke customer_archive, lihat MigrateWithConfig.
+var columnMigrations = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{CustomerTable, "status", "VARCHAR(20) NOT NULL DEFAULT 'active'"},
+	{CustomerTable, "deleted_at", "TIMESTAMP NULL"},
+	{CustomerTable, "updated_at", "TIMESTAMP(6) NULL"},
+}
+
+// kolom yang namanya diperbaiki, dijalankan sebelum columnMigrations
+var columnRenames = []struct {
+	table      string
+	from       string
+	to         string
+	definition string
+}{
+	{CustomerTable, "balannce", "balance", "INT DEFAULT 0"},
+}
+
+var indexMigrations = []struct {
+	table   string
+	index   string
+	columns string
+}{
+	// dipakai RecentCustomers supaya ORDER BY created_at tidak perlu filesort
+	{CustomerTable, "idx_customer_created_at", "created_at"},
+	// lookup berdasarkan email, misalnya MergeByEmail
+	{CustomerTable, "idx_customer_email", "email"},
+}
+
+// Migrate creates every table used by this repository if it does not exist yet
+// and brings existing tables up to date with the renamed, added columns and indexes
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return MigrateWithConfig(ctx, db, DefaultConfig())
+}
+
+// MigrateWithConfig is Migrate for the customer and user tables named with config.TablePrefix
+func MigrateWithConfig(ctx context.Context, db *sql.DB, config Config) error {
+	if err := config.validateTablePrefix(); err != nil {
+		return err
+	}
+
+	for _, script := range migrations(config) {
+		_, err := db.ExecContext(ctx, script)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rename := range columnRenames {
+		err := renameColumnIfPresent(ctx, db, config.Table(rename.table), rename.from, rename.to, rename.definition)
+		if err != nil {
+			return err
+		}
+	}
+
+	// dibuat sebelum columnMigrations supaya archive yang sudah ada ikut mendapat kolom baru
+	script := "CREATE TABLE IF NOT EXISTS " + config.QuotedTable(CustomerArchiveTable) + " LIKE " + config.QuotedTable(CustomerTable)
+	_, err := db.ExecContext(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range columnMigrations {
+		tables := []string{migration.table}
+		if migration.table == CustomerTable {
+			// archive dibuat dari customer lama, jadi harus mendapat kolom yang sama
+			tables = append(tables, CustomerArchiveTable)
+		}
+		for _, table := range tables {
+			err := addColumnIfMissing(ctx, db, config.Table(table), migration.column, migration.definition)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, migration := range indexMigrations {
+		err := addIndexIfMissing(ctx, db, config.Table(migration.table), migration.index, migration.columns)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addColumnIfMissing(ctx context.Context, db *sql.DB, table string, column string, definition string) error {
+	var total int
+	script := "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?"
+	err := db.QueryRowContext(ctx, script, table, column).Scan(&total)
+	if err != nil || total > 0 {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "ALTER TABLE `"+table+"` ADD COLUMN `"+column+"` "+definition)
+	return err
+}
+
+func addIndexIfMissing(ctx context.Context, db *sql.DB, table string, index string, columns string) error {
+	var total int
+	script := "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?"
+	err := db.QueryRowContext(ctx, script, table, index).Scan(&total)
+	if err != nil || total > 0 {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "CREATE INDEX `"+index+"` ON `"+table+"` ("+columns+")")
+	return err
+}
+
+// RenameBalanceColumn renames the misspelled customer.balannce to balance. It does
+// nothing when the column was already renamed, so it is safe to run again.
+func RenameBalanceColumn(ctx context.Context, db *sql.DB) error {
+	return RenameBalanceColumnWithConfig(ctx, db, DefaultConfig())
+}
+
+// RenameBalanceColumnWithConfig is RenameBalanceColumn for the customer table named with config.TablePrefix
+func RenameBalanceColumnWithConfig(ctx context.Context, db *sql.DB, config Config) error {
+	return renameColumnIfPresent(ctx, db, config.Table(CustomerTable), "balannce", "balance", "INT DEFAULT 0")
+}
+
+func renameColumnIfPresent(ctx context.Context, db *sql.DB, table string, from string, to string, definition string) error {
+	columns, err := DescribeTable(ctx, db, table)
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if column.Name == from {
+			_, err = db.ExecContext(ctx, "ALTER TABLE `"+table+"` CHANGE `"+from+"` `"+to+"` "+definition)
+			return err
+		}
+	}
+	return nil
+}
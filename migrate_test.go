@@ -0,0 +1,46 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRenameBalanceColumn(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	ctx := context.Background()
+
+	// kembalikan ke skema lama dengan nama kolom yang salah ketik
+	_, err := db.ExecContext(ctx, "ALTER TABLE customer CHANGE balance balannce INT DEFAULT 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.ExecContext(ctx, "INSERT INTO customer(id, name, balannce) VALUES ('nafis', 'Nafis', 1000)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for run := 0; run < 2; run++ {
+		if err := RenameBalanceColumn(ctx, db); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		columns, err := DescribeTable(ctx, db, "customer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := map[string]bool{}
+		for _, column := range columns {
+			names[column.Name] = true
+		}
+		if !names["balance"] || names["balannce"] {
+			t.Fatalf("run %d: expected balance instead of balannce, got %+v", run, columns)
+		}
+	}
+
+	var balance int
+	if err := db.QueryRowContext(ctx, "SELECT balance FROM customer WHERE id = 'nafis'").Scan(&balance); err != nil {
+		t.Fatal(err)
+	}
+	if balance != 1000 {
+		t.Fatalf("expected the data to survive the rename, got %d", balance)
+	}
+}
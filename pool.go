@@ -0,0 +1,91 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+type PoolStatus struct {
+	MaxOpenConnections int
+	OpenConnections    int
+	InUse              int
+	Idle               int
+	WaitCount          int64
+	WaitDuration       time.Duration
+}
+
+func GetPoolStatus(db *sql.DB) PoolStatus {
+	stats := db.Stats()
+	return PoolStatus{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration,
+	}
+}
+
+// WaitDelta returns how many connection waits happened between two samples and how long they took
+func WaitDelta(before PoolStatus, after PoolStatus) (count int64, dur time.Duration) {
+	return after.WaitCount - before.WaitCount, after.WaitDuration - before.WaitDuration
+}
+
+// Conn reserves one connection of the pool for the caller, release hands it back.
+// Every query on the *sql.Conn runs on the same session, so SET SESSION variables,
+// temporary tables and locks carry over from one query to the next. They also stay
+// on the connection after release, the driver does not reset the session.
+func Conn(ctx context.Context, db *sql.DB) (*sql.Conn, func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+// StartStatsSampler calls sink with the pool status every interval until ctx is cancelled
+func StartStatsSampler(ctx context.Context, db *sql.DB, interval time.Duration, sink func(PoolStatus)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sink(GetPoolStatus(db))
+			}
+		}
+	}()
+}
+
+// StressPool runs concurrency goroutines that each execute perWorker quick queries.
+// The returned WaitCount and WaitDuration only cover the waits caused by this run.
+func StressPool(ctx context.Context, db *sql.DB, concurrency int, perWorker int) (PoolStatus, error) {
+	before := GetPoolStatus(db)
+
+	var group sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < concurrency; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for j := 0; j < perWorker; j++ {
+				var result int
+				err := db.QueryRowContext(ctx, "SELECT 1").Scan(&result)
+				if err != nil {
+					once.Do(func() { firstErr = err })
+					return
+				}
+			}
+		}()
+	}
+	group.Wait()
+
+	after := GetPoolStatus(db)
+	after.WaitCount, after.WaitDuration = WaitDelta(before, after)
+	return after, firstErr
+}
@@ -0,0 +1,135 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStatsSampler(t *testing.T) {
+	db := GetConnection()
+	defer db.Close()
+
+	before := runtime.NumGoroutine()
+
+	var mutex sync.Mutex
+	var samples []PoolStatus
+	ctx, cancel := context.WithCancel(context.Background())
+	StartStatsSampler(ctx, db, 10*time.Millisecond, func(status PoolStatus) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		samples = append(samples, status)
+	})
+
+	time.Sleep(55 * time.Millisecond)
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+
+	mutex.Lock()
+	collected := len(samples)
+	mutex.Unlock()
+	if collected < 3 {
+		t.Fatalf("expected at least 3 samples, got %d", collected)
+	}
+	if samples[0].MaxOpenConnections != 100 {
+		t.Fatalf("expected max open connections 100, got %d", samples[0].MaxOpenConnections)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	mutex.Lock()
+	after := len(samples)
+	mutex.Unlock()
+	if after != collected {
+		t.Fatalf("sampler still running after cancel: %d -> %d samples", collected, after)
+	}
+	if goroutines := runtime.NumGoroutine(); goroutines > before {
+		t.Fatalf("leaked goroutine: %d before, %d after", before, goroutines)
+	}
+}
+
+func TestStressPoolSmallPool(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	db.SetMaxOpenConns(2)
+
+	status, err := StressPool(context.Background(), db, 20, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.WaitCount == 0 {
+		t.Fatal("expected a pool of 2 to make goroutines wait")
+	}
+}
+
+func TestStressPoolLargePool(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	db.SetMaxOpenConns(50)
+
+	status, err := StressPool(context.Background(), db, 20, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.WaitCount != 0 {
+		t.Fatalf("expected no waits with a large pool, got %d", status.WaitCount)
+	}
+}
+
+func TestWaitDelta(t *testing.T) {
+	count, dur := WaitDelta(PoolStatus{WaitCount: 3, WaitDuration: time.Second}, PoolStatus{WaitCount: 5, WaitDuration: 3 * time.Second})
+	if count != 2 || dur != 2*time.Second {
+		t.Fatalf("expected 2 waits over 2s, got %d over %s", count, dur)
+	}
+
+	db, _ := SetupTestDB(t)
+	db.SetMaxOpenConns(1)
+
+	before := GetPoolStatus(db)
+	var group sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			db.Exec("DO SLEEP(0.01)")
+		}()
+	}
+	group.Wait()
+
+	count, dur = WaitDelta(before, GetPoolStatus(db))
+	if count <= 0 || dur <= 0 {
+		t.Fatalf("expected contention on a pool of 1, got %d waits over %s", count, dur)
+	}
+}
+
+func TestConnPinsSession(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	ctx := context.Background()
+
+	conn, release, err := Conn(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// release kedua hanya mengembalikan sql.ErrConnDone yang diabaikan
+	defer release()
+
+	if _, err := conn.ExecContext(ctx, "SET @prioritas = 'tinggi'"); err != nil {
+		t.Fatal(err)
+	}
+	for run := 0; run < 2; run++ {
+		var value string
+		if err := conn.QueryRowContext(ctx, "SELECT @prioritas").Scan(&value); err != nil {
+			t.Fatal(err)
+		}
+		if value != "tinggi" {
+			t.Fatalf("run %d: expected the session variable on the same conn, got %q", run, value)
+		}
+	}
+	if inUse := db.Stats().InUse; inUse != 1 {
+		t.Fatalf("expected the conn to be in use, got %d", inUse)
+	}
+
+	release()
+	if stats := db.Stats(); stats.InUse != 0 || stats.Idle < 1 {
+		t.Fatalf("expected the conn back in the pool, got %d in use and %d idle", stats.InUse, stats.Idle)
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+)
+
+// BulkInsertInTx prepares the insert once on the transaction and reuses it for
+// every customer, a failing row or a cancelled ctx rolls the whole batch back.
+func BulkInsertInTx(ctx context.Context, db *sql.DB, customers []entity.Customer) error {
+	return BulkInsertInTxWithConfig(ctx, db, belajar_golang_database.DefaultConfig(), customers)
+}
+
+// BulkInsertInTxWithConfig is BulkInsertInTx with the Clock, TablePrefix and
+// normalization options of config, the same ones Insert applies
+func BulkInsertInTxWithConfig(ctx context.Context, db *sql.DB, config belajar_golang_database.Config, customers []entity.Customer) error {
+	repository := NewCustomerRepositoryWithConfig(db, config).(*customerRepositoryImpl)
+	return WithTransaction(ctx, db, func(tx *sql.Tx) error {
+		script := "INSERT INTO " + repository.Table + "(" + customerColumns + ") VALUES " + customerPlaceholders
+		statement, err := tx.PrepareContext(ctx, script)
+		if err != nil {
+			return err
+		}
+		defer statement.Close()
+
+		now := repository.Clock.Now()
+		for _, customer := range customers {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			customer = repository.prepareInsert(customer, now)
+			_, err := statement.ExecContext(ctx, customerArgs(customer)...)
+			if isDuplicateKey(err) {
+				return ErrDuplicateKey
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
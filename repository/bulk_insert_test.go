@@ -0,0 +1,80 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestBulkInsertInTx(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	customers := make([]entity.Customer, 100)
+	for i := range customers {
+		customers[i] = entity.Customer{Id: "bulk" + strconv.Itoa(i), Name: "Bulk " + strconv.Itoa(i)}
+	}
+	err := BulkInsertInTx(ctx, db, customers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := NewCustomerRepository(db).Count(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 100 {
+		t.Fatalf("expected 100 customers, got %d", total)
+	}
+}
+
+func TestBulkInsertInTxRollback(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	customers := make([]entity.Customer, 100)
+	for i := range customers {
+		customers[i] = entity.Customer{Id: "bulk" + strconv.Itoa(i), Name: "Bulk " + strconv.Itoa(i)}
+	}
+	// id kembar di tengah batch
+	customers[50].Id = "bulk0"
+
+	err := BulkInsertInTx(ctx, db, customers)
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+
+	total, err := NewCustomerRepository(db).Count(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("expected the whole batch to roll back, got %d customers", total)
+	}
+}
+
+func TestBulkInsertInTxWithConfig(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	config := belajar_golang_database.DefaultConfig()
+	config.Clock = fixedClock{now: now}
+	config.NormalizeNames = true
+	err := BulkInsertInTxWithConfig(ctx, db, config, []entity.Customer{{Id: "nafis", Name: "  nafis   arya "}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customer, err := NewCustomerRepository(db).FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != "Nafis Arya" || !customer.CreatedAt.Equal(now) {
+		t.Fatalf("expected the normalized name and created_at from the Clock, got %q at %s", customer.Name, customer.CreatedAt)
+	}
+}
@@ -0,0 +1,22 @@
+package repository
+
+// MySQL menolak statement dengan lebih dari 65535 placeholder
+const maxPlaceholders = 65535
+
+// Chunk splits items into slices of at most size items.
+// A size <= 0 returns all items as a single chunk.
+func Chunk[T any](items []T, size int) [][]T {
+	if len(items) == 0 {
+		return [][]T{}
+	}
+	if size <= 0 {
+		return [][]T{items}
+	}
+
+	chunks := make([][]T, 0, (len(items)+size-1)/size)
+	for size < len(items) {
+		chunks = append(chunks, items[:size:size])
+		items = items[size:]
+	}
+	return append(chunks, items)
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkEven(t *testing.T) {
+	chunks := Chunk([]int{1, 2, 3, 4}, 2)
+	expected := [][]int{{1, 2}, {3, 4}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("expected %v, got %v", expected, chunks)
+	}
+}
+
+func TestChunkRemainder(t *testing.T) {
+	chunks := Chunk([]int{1, 2, 3, 4, 5}, 2)
+	expected := [][]int{{1, 2}, {3, 4}, {5}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("expected %v, got %v", expected, chunks)
+	}
+}
+
+func TestChunkEmpty(t *testing.T) {
+	if chunks := Chunk([]int{}, 2); len(chunks) != 0 {
+		t.Fatalf("expected no chunks, got %v", chunks)
+	}
+	if chunks := Chunk[int](nil, 0); len(chunks) != 0 {
+		t.Fatalf("expected no chunks, got %v", chunks)
+	}
+}
+
+func TestChunkSingleOversized(t *testing.T) {
+	chunks := Chunk([]string{"nafis"}, 10)
+	expected := [][]string{{"nafis"}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("expected %v, got %v", expected, chunks)
+	}
+}
+
+func TestChunkNonPositiveSize(t *testing.T) {
+	chunks := Chunk([]int{1, 2, 3}, 0)
+	expected := [][]int{{1, 2, 3}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("expected %v, got %v", expected, chunks)
+	}
+}
+
+func TestChunkDoesNotShareCapacity(t *testing.T) {
+	items := []int{1, 2, 3, 4}
+	chunks := Chunk(items, 2)
+	chunks[0] = append(chunks[0], 99)
+	if items[2] != 3 {
+		t.Fatalf("appending to a chunk overwrote the next chunk: %v", items)
+	}
+}
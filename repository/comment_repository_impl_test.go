@@ -11,7 +11,8 @@ import (
 )
 
 func TestCommentInsert(t *testing.T) {
-	commentRepository := NewCommentRepository(belajar_golang_database.GetConnection())
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	commentRepository := NewCommentRepository(db)
 
 	ctx := context.Background()
 	comment := entity.Comment{
@@ -21,18 +22,27 @@ func TestCommentInsert(t *testing.T) {
 
 	result, err := commentRepository.Insert(ctx, comment)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	fmt.Println(result)
 }
 
 func TestFindById(t *testing.T) {
-	commentRepository := NewCommentRepository(belajar_golang_database.GetConnection())
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	commentRepository := NewCommentRepository(db)
 
-	comment, err := commentRepository.FindById(context.Background(), 35)
+	inserted, err := commentRepository.Insert(context.Background(), entity.Comment{
+		Email:   "repository@test.com",
+		Comment: "Test Repository",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comment, err := commentRepository.FindById(context.Background(), inserted.Id)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	fmt.Println(comment)
@@ -40,11 +50,12 @@ func TestFindById(t *testing.T) {
 }
 
 func TestFindAll(t *testing.T) {
-	commentRepository := NewCommentRepository(belajar_golang_database.GetConnection())
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	commentRepository := NewCommentRepository(db)
 
 	comments, err := commentRepository.FindAll(context.Background())
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	for _, comment := range comments {
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// condition accumulates AND-ed WHERE conditions on whitelisted columns
+type condition struct {
+	columns map[string]bool
+	parts   []string
+	args    []any
+	err     error
+}
+
+func newCondition(columns map[string]bool) *condition {
+	return &condition{columns: columns}
+}
+
+func (c *condition) add(column string, operator string, args ...any) *condition {
+	if c.err != nil {
+		return c
+	}
+	if !c.columns[column] {
+		c.err = fmt.Errorf("%w: unknown column %q", ErrInvalidArgument, column)
+		return c
+	}
+
+	quoted, err := quoteIdent(column)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	c.parts = append(c.parts, quoted+" "+operator)
+	c.args = append(c.args, args...)
+	return c
+}
+
+func (c *condition) Eq(column string, value any) *condition {
+	return c.add(column, "= ?", value)
+}
+
+func (c *condition) Gt(column string, value any) *condition {
+	return c.add(column, "> ?", value)
+}
+
+func (c *condition) Like(column string, pattern string) *condition {
+	return c.add(column, "LIKE ?", pattern)
+}
+
+func (c *condition) IsNull(column string) *condition {
+	return c.add(column, "IS NULL")
+}
+
+func (c *condition) IsNotNull(column string) *condition {
+	return c.add(column, "IS NOT NULL")
+}
+
+// Build returns "WHERE ..." with its arguments, or an empty string without conditions
+func (c *condition) Build() (string, []any) {
+	if len(c.parts) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(c.parts, " AND "), c.args
+}
+
+func (c *condition) Err() error {
+	return c.err
+}
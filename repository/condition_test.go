@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var conditionColumns = map[string]bool{"name": true, "rating": true, "email": true}
+
+func TestConditionEmpty(t *testing.T) {
+	clause, args := newCondition(conditionColumns).Build()
+	if clause != "" || len(args) != 0 {
+		t.Fatalf("expected empty clause, got %q %v", clause, args)
+	}
+}
+
+func TestConditionSingle(t *testing.T) {
+	clause, args := newCondition(conditionColumns).Eq("name", "Nafis").Build()
+	if clause != "WHERE `name` = ?" || !reflect.DeepEqual(args, []any{"Nafis"}) {
+		t.Fatalf("unexpected clause %q %v", clause, args)
+	}
+}
+
+func TestConditionMixed(t *testing.T) {
+	clause, args := newCondition(conditionColumns).
+		Gt("rating", 4.5).
+		IsNull("email").
+		Like("name", "Na%").
+		Build()
+
+	expected := "WHERE `rating` > ? AND `email` IS NULL AND `name` LIKE ?"
+	if clause != expected {
+		t.Fatalf("expected %q, got %q", expected, clause)
+	}
+	if !reflect.DeepEqual(args, []any{4.5, "Na%"}) {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
+
+func TestConditionUnknownColumn(t *testing.T) {
+	where := newCondition(conditionColumns).Eq("password", "rahasia")
+	if !errors.Is(where.Err(), ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", where.Err())
+	}
+	if clause, _ := where.Build(); clause != "" {
+		t.Fatalf("expected rejected column to be left out, got %q", clause)
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"strconv"
+	"testing"
+)
+
+const benchmarkRows = 100
+
+func benchmarkCustomers() []entity.Customer {
+	customers := make([]entity.Customer, benchmarkRows)
+	for i := range customers {
+		customers[i] = entity.Customer{Id: "bench" + strconv.Itoa(i), Name: "Bench " + strconv.Itoa(i)}
+	}
+	return customers
+}
+
+func BenchmarkInsertSingle(b *testing.B) {
+	db, _ := belajar_golang_database.SetupTestDB(b)
+	customerRepository := NewCustomerRepository(db)
+	customers := benchmarkCustomers()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		if _, err := TruncateTables(ctx, db, "customer"); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		for _, customer := range customers {
+			if _, err := customerRepository.Insert(ctx, customer); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkInsertBatch(b *testing.B) {
+	db, _ := belajar_golang_database.SetupTestDB(b)
+	customerRepository := NewCustomerRepository(db)
+	customers := benchmarkCustomers()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		if _, err := TruncateTables(ctx, db, "customer"); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		if err := customerRepository.InsertBatch(ctx, customers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
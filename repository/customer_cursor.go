@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"belajar-golang-database/entity"
+	"database/sql"
+)
+
+// CustomerCursor iterates customers one row at a time without loading them all.
+// The caller must Close it, usually with defer right after the query.
+type CustomerCursor struct {
+	rows       *sql.Rows
+	repository *customerRepositoryImpl
+}
+
+func (cursor *CustomerCursor) Next() bool {
+	return cursor.rows.Next()
+}
+
+func (cursor *CustomerCursor) Scan() (entity.Customer, error) {
+	return cursor.repository.scanCustomer(cursor.rows)
+}
+
+// Err reports the error that stopped Next, nil when the rows were exhausted normally
+func (cursor *CustomerCursor) Err() error {
+	return cursor.rows.Err()
+}
+
+// Close releases the connection, calling it more than once is safe
+func (cursor *CustomerCursor) Close() error {
+	return cursor.rows.Close()
+}
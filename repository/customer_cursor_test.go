@@ -0,0 +1,49 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"testing"
+)
+
+func TestCustomerCursor(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+		entity.Customer{Id: "nadia", Name: "Nadia"},
+	)
+
+	cursor, err := NewCustomerRepository(db).FindCursor(context.Background(), CustomerFilter{NameLike: "na"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+
+	var ids []string
+	for cursor.Next() {
+		customer, err := cursor.Scan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, customer.Id)
+	}
+	if err := cursor.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "nadia" || ids[1] != "nafis" {
+		t.Fatalf("expected [nadia nafis], got %v", ids)
+	}
+
+	if err := cursor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.Close(); err != nil {
+		t.Fatalf("expected a second Close to be a no-op, got %v", err)
+	}
+	if cursor.Next() {
+		t.Fatal("expected Next to be false after Close")
+	}
+}
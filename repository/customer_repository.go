@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"io"
+	"time"
+)
+
+type CustomerFilter struct {
+	NameLike    string
+	EmailIsNull *bool
+}
+
+type CustomerRepository interface {
+	Insert(ctx context.Context, customer entity.Customer) (entity.Customer, error)
+	InsertTx(ctx context.Context, tx *sql.Tx, customer entity.Customer) (entity.Customer, error)
+	InsertIgnore(ctx context.Context, customer entity.Customer) (bool, error)
+	InsertBatch(ctx context.Context, customers []entity.Customer) error
+	InsertFromChannel(ctx context.Context, in <-chan entity.Customer) (int, error)
+	UpsertBatch(ctx context.Context, customers []entity.Customer) error
+	FindById(ctx context.Context, id string) (entity.Customer, error)
+	FindByIdAnyArchive(ctx context.Context, id string) (entity.Customer, bool, error)
+	FindByIdForUpdate(ctx context.Context, tx *sql.Tx, id string) (entity.Customer, error)
+	FindByIdColumns(ctx context.Context, id string, columns []string) (entity.Customer, error)
+	FindByIdsChunked(ctx context.Context, ids []string, chunkSize int) ([]entity.Customer, error)
+	FindByIdsMap(ctx context.Context, ids []string) (map[string]entity.Customer, error)
+	FindAll(ctx context.Context) ([]entity.Customer, error)
+	Count(ctx context.Context) (int, error)
+	CountDistinct(ctx context.Context, column string) (int, error)
+	Exists(ctx context.Context, id string) (bool, error)
+	TotalBalance(ctx context.Context) (int64, error)
+	BalancePercentile(ctx context.Context, p float64) (int32, error)
+	BalanceHistory(ctx context.Context, id string) ([]entity.BalanceEvent, error)
+	CountWhere(ctx context.Context, filter CustomerFilter) (int, error)
+	CountByMarried(ctx context.Context) (married int, single int, err error)
+	FindDuplicateNames(ctx context.Context) (map[string]int, error)
+	FindFirst(ctx context.Context, limit int) ([]entity.Customer, error)
+	SampleCustomers(ctx context.Context, n int, seed int64) ([]entity.Customer, error)
+	RecentCustomers(ctx context.Context, n int) ([]entity.Customer, error)
+	ForEachPage(ctx context.Context, pageSize int, fn func([]entity.Customer) error) error
+	FindByRatingBetween(ctx context.Context, min float64, max float64, limit int, offset int) ([]entity.Customer, error)
+	FindMissingEmail(ctx context.Context, limit int, offset int) ([]entity.Customer, error)
+	Find(ctx context.Context, filter CustomerFilter) ([]entity.Customer, error)
+	FindCursor(ctx context.Context, filter CustomerFilter) (*CustomerCursor, error)
+	SearchByName(ctx context.Context, name string) ([]entity.Customer, error)
+	Update(ctx context.Context, customer entity.Customer) (int64, error)
+	UpdateBatch(ctx context.Context, customers []entity.Customer) error
+	UpdateAndFetch(ctx context.Context, customer entity.Customer) (entity.Customer, error)
+	UpdateMarriedForAll(ctx context.Context, married bool) (int64, error)
+	AddBalanceWhereRatingAbove(ctx context.Context, minRating float64, delta int32) (int64, error)
+	Anonymize(ctx context.Context, id string) error
+	Archive(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) (int64, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	ApplyDiff(ctx context.Context, id string, changes map[string]any) error
+	Touch(ctx context.Context, ids []string) (int64, error)
+	DeleteByIds(ctx context.Context, ids []string) (int64, error)
+	InsertIdempotent(ctx context.Context, key string, customer entity.Customer) error
+	FindOrCreate(ctx context.Context, customer entity.Customer) (entity.Customer, bool, error)
+	CloneCustomer(ctx context.Context, srcId string, newId string) (entity.Customer, error)
+	ExportNDJSON(ctx context.Context, w io.Writer) error
+	StreamExport(ctx context.Context, w io.Writer, format string) error
+	MergeByEmail(ctx context.Context, email string) (string, error)
+	Onboard(ctx context.Context, customer entity.Customer, openingBalance int32) (entity.Customer, error)
+}
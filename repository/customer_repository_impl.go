@@ -0,0 +1,1210 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const customerColumns = "id, name, email, balance, rating, created_at, birth_date, married, status"
+
+// semua kolom yang disalin Archive, termasuk yang tidak dibaca scanCustomer
+const customerArchiveColumns = customerColumns + ", deleted_at, updated_at"
+
+type customerRepositoryImpl struct {
+	DB                *sql.DB
+	Table             string
+	ArchiveTable      string
+	Location          *time.Location
+	MaxRows           int
+	Clock             belajar_golang_database.Clock
+	EmptyStringAsNull bool
+	NameRules         []func(string) string
+}
+
+func NewCustomerRepository(db *sql.DB) CustomerRepository {
+	return NewCustomerRepositoryWithConfig(db, belajar_golang_database.DefaultConfig())
+}
+
+func NewCustomerRepositoryWithConfig(db *sql.DB, config belajar_golang_database.Config) CustomerRepository {
+	location := config.Location
+	if location == nil {
+		location = time.UTC
+	}
+	clock := config.Clock
+	if clock == nil {
+		clock = belajar_golang_database.RealClock
+	}
+	table := config.QuotedTable(belajar_golang_database.CustomerTable)
+	archiveTable := config.QuotedTable(belajar_golang_database.CustomerArchiveTable)
+	var nameRules []func(string) string
+	if config.NormalizeNames {
+		nameRules = DefaultNameRules
+	}
+	return &customerRepositoryImpl{DB: db, Table: table, ArchiveTable: archiveTable, Location: location, MaxRows: config.MaxRows,
+		Clock: clock, EmptyStringAsNull: config.EmptyStringAsNull, NameRules: nameRules}
+}
+
+// scanCustomer reads the non nullable fields through sql.Null first, so a NULL in one
+// of them is reported with the column name instead of a generic conversion error
+func (repository *customerRepositoryImpl) scanCustomer(rows *sql.Rows) (entity.Customer, error) {
+	customer := entity.Customer{}
+	var id, name sql.Null[string]
+	var balance sql.Null[int32]
+	var rating sql.Null[float64]
+	var createdAt sql.Null[time.Time]
+	var married sql.Null[entity.Bool]
+	var status sql.Null[entity.Status]
+	err := rows.Scan(&id, &name, &customer.Email, &balance,
+		&rating, &createdAt, &customer.BirthDate, &married, &status)
+	if err != nil {
+		return customer, err
+	}
+
+	for _, column := range []struct {
+		name  string
+		valid bool
+	}{{"id", id.Valid}, {"name", name.Valid}, {"balance", balance.Valid}, {"rating", rating.Valid},
+		{"created_at", createdAt.Valid}, {"married", married.Valid}, {"status", status.Valid}} {
+		if !column.valid {
+			return customer, fmt.Errorf("%w: column %s", ErrUnexpectedNull, column.name)
+		}
+	}
+
+	customer.Id, customer.Name, customer.Balance, customer.Rating = id.V, name.V, balance.V, rating.V
+	customer.Married, customer.Status = married.V, status.V
+	// disimpan dalam UTC, dikonversi ke Location hanya saat dibaca
+	customer.CreatedAt = createdAt.V.In(repository.Location)
+	return customer, nil
+}
+
+// findMany runs an unbounded list query, capped at MaxRows when it is set.
+// One extra row is fetched to tell a full page apart from a truncated result.
+func (repository *customerRepositoryImpl) findMany(ctx context.Context, script string, args ...any) ([]entity.Customer, error) {
+	if repository.MaxRows > 0 {
+		script += " LIMIT " + strconv.Itoa(repository.MaxRows+1)
+	}
+
+	customers, err := Collect(ctx, repository.DB, script, repository.scanCustomer, args...)
+	if err != nil {
+		return nil, err
+	}
+	if repository.MaxRows > 0 && len(customers) > repository.MaxRows {
+		return nil, ErrResultTooLarge
+	}
+	return customers, nil
+}
+
+// escapeLike escapes the LIKE wildcards so user input is matched literally
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
+// customerFields maps every whitelisted column to the field it is scanned into
+func customerFields(customer *entity.Customer) map[string]any {
+	return map[string]any{
+		"id":         &customer.Id,
+		"name":       &customer.Name,
+		"email":      &customer.Email,
+		"balance":    &customer.Balance,
+		"rating":     &customer.Rating,
+		"created_at": &customer.CreatedAt,
+		"birth_date": &customer.BirthDate,
+		"married":    &customer.Married,
+		"status":     &customer.Status,
+	}
+}
+
+var customerColumnSet = func() map[string]bool {
+	columns := map[string]bool{}
+	for column := range customerFields(&entity.Customer{}) {
+		columns[column] = true
+	}
+	return columns
+}()
+
+const customerPlaceholders = "(?,?,?,?,?,?,?,?,?)"
+
+// jumlah baris maksimal per statement batch, 9 placeholder per customer
+const customerBatchSize = maxPlaceholders / 9
+
+func customerArgs(customer entity.Customer) []any {
+	return []any{customer.Id, customer.Name, customer.Email, customer.Balance,
+		customer.Rating, customer.CreatedAt, customer.BirthDate, customer.Married, customer.Status}
+}
+
+// withInsertDefaults fills the values a new customer gets when they are left empty
+func withInsertDefaults(customer entity.Customer, now time.Time) entity.Customer {
+	if customer.Id == "" {
+		customer.Id = GenerateId(customerIdPrefix)
+	}
+	if customer.CreatedAt.IsZero() {
+		customer.CreatedAt = now
+	}
+	customer.CreatedAt = customer.CreatedAt.UTC()
+	if customer.Status == "" {
+		customer.Status = entity.StatusActive
+	}
+	return customer
+}
+
+func insertCustomer(ctx context.Context, db DBTX, table string, customer entity.Customer, now time.Time) (entity.Customer, error) {
+	customer = withInsertDefaults(customer, now)
+
+	script := "INSERT INTO " + table + "(" + customerColumns + ") VALUES " + customerPlaceholders
+	_, err := db.ExecContext(ctx, script, customerArgs(customer)...)
+	if isDuplicateKey(err) {
+		return customer, ErrDuplicateKey
+	}
+	return customer, err
+}
+
+// normalize applies the repository options to a customer about to be written
+func (repository *customerRepositoryImpl) normalize(customer entity.Customer) entity.Customer {
+	if repository.EmptyStringAsNull && customer.Email.Valid && customer.Email.String == "" {
+		customer.Email = sql.NullString{}
+	}
+	customer.Name = NormalizeString(customer.Name, repository.NameRules...)
+	return customer
+}
+
+// prepareInsert is applied to every new customer, whichever method inserts it
+func (repository *customerRepositoryImpl) prepareInsert(customer entity.Customer, now time.Time) entity.Customer {
+	return withInsertDefaults(repository.normalize(customer), now)
+}
+
+// insert is insertCustomer with the repository options applied
+func (repository *customerRepositoryImpl) insert(ctx context.Context, db DBTX, customer entity.Customer, now time.Time) (entity.Customer, error) {
+	return insertCustomer(ctx, db, repository.Table, repository.prepareInsert(customer, now), now)
+}
+
+func (repository *customerRepositoryImpl) Insert(ctx context.Context, customer entity.Customer) (entity.Customer, error) {
+	return repository.insert(ctx, repository.DB, customer, repository.Clock.Now())
+}
+
+// InsertTx inserts the customer on tx together with a customer.created outbox event,
+// the event only becomes visible when the caller commits tx
+func (repository *customerRepositoryImpl) InsertTx(ctx context.Context, tx *sql.Tx, customer entity.Customer) (entity.Customer, error) {
+	now := repository.Clock.Now()
+	customer, err := repository.insert(ctx, tx, customer, now)
+	if err != nil {
+		return customer, err
+	}
+	return customer, writeOutbox(ctx, tx, topicCustomerCreated, customer, now)
+}
+
+// InsertIgnore skips the row silently when the id already exists and reports whether it was inserted.
+// INSERT IGNORE also turns other data errors into warnings, so only use it for trusted input.
+func (repository *customerRepositoryImpl) InsertIgnore(ctx context.Context, customer entity.Customer) (bool, error) {
+	customer = repository.prepareInsert(customer, repository.Clock.Now())
+
+	script := "INSERT IGNORE INTO " + repository.Table + "(" + customerColumns + ") VALUES " + customerPlaceholders
+	result, err := repository.DB.ExecContext(ctx, script, customerArgs(customer)...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (repository *customerRepositoryImpl) FindById(ctx context.Context, id string) (entity.Customer, error) {
+	return repository.findById(ctx, repository.DB, id)
+}
+
+func (repository *customerRepositoryImpl) findById(ctx context.Context, db DBTX, id string) (entity.Customer, error) {
+	return repository.findByIdIn(ctx, db, repository.Table, id)
+}
+
+// FindByIdAnyArchive looks in the live table first and then in the archive,
+// fromArchive reports where the customer was found
+func (repository *customerRepositoryImpl) FindByIdAnyArchive(ctx context.Context, id string) (entity.Customer, bool, error) {
+	customer, err := repository.findById(ctx, repository.DB, id)
+	if !errors.Is(err, ErrNotFound) {
+		return customer, false, err
+	}
+	customer, err = repository.findByIdIn(ctx, repository.DB, repository.ArchiveTable, id)
+	return customer, err == nil, err
+}
+
+func findByIdScript(table string) string {
+	return "SELECT " + customerColumns + " FROM " + table + " WHERE id = ? LIMIT 1"
+}
+
+func (repository *customerRepositoryImpl) findByIdIn(ctx context.Context, db DBTX, table string, id string) (entity.Customer, error) {
+	rows, err := db.QueryContext(ctx, findByIdScript(table), id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		// ada
+		return repository.scanCustomer(rows)
+	} else {
+		// tidak ada
+		return entity.Customer{}, fmt.Errorf("id %s: %w", id, ErrNotFound)
+	}
+}
+
+func (repository *customerRepositoryImpl) FindAll(ctx context.Context) ([]entity.Customer, error) {
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + " ORDER BY id"
+	return repository.findMany(ctx, script)
+}
+
+// filterWhere builds the WHERE clause shared by Find and CountWhere
+func filterWhere(filter CustomerFilter) (string, []any, error) {
+	where := newCondition(customerColumnSet)
+	if filter.NameLike != "" {
+		where.Like("name", "%"+escapeLike(filter.NameLike)+"%")
+	}
+	if filter.EmailIsNull != nil {
+		if *filter.EmailIsNull {
+			where.IsNull("email")
+		} else {
+			where.IsNotNull("email")
+		}
+	}
+
+	clause, args := where.Build()
+	if clause != "" {
+		clause = " " + clause
+	}
+	return clause, args, where.Err()
+}
+
+func (repository *customerRepositoryImpl) Find(ctx context.Context, filter CustomerFilter) ([]entity.Customer, error) {
+	where, args, err := filterWhere(filter)
+	if err != nil {
+		return nil, err
+	}
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + where + " ORDER BY id"
+	return repository.findMany(ctx, script, args...)
+}
+
+// FindCursor is Find without MaxRows, returning a cursor instead of the whole slice
+func (repository *customerRepositoryImpl) FindCursor(ctx context.Context, filter CustomerFilter) (*CustomerCursor, error) {
+	where, args, err := filterWhere(filter)
+	if err != nil {
+		return nil, err
+	}
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + where + " ORDER BY id"
+	rows, err := repository.DB.QueryContext(ctx, script, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomerCursor{rows: rows, repository: repository}, nil
+}
+
+func (repository *customerRepositoryImpl) CountWhere(ctx context.Context, filter CustomerFilter) (int, error) {
+	where, args, err := filterWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+	return QueryScalar[int](ctx, repository.DB, "SELECT COUNT(*) FROM "+repository.Table+where, args...)
+}
+
+func (repository *customerRepositoryImpl) Onboard(ctx context.Context, customer entity.Customer, openingBalance int32) (entity.Customer, error) {
+	customer.Balance = openingBalance
+	now := repository.Clock.Now()
+	err := WithTransaction(ctx, repository.DB, func(tx *sql.Tx) error {
+		var err error
+		customer, err = repository.insert(ctx, tx, customer, now)
+		if err != nil {
+			return err
+		}
+
+		script := "INSERT INTO balance_audit(customer_id, amount, description) VALUES (?,?,?)"
+		_, err = tx.ExecContext(ctx, script, customer.Id, openingBalance, "opening balance")
+		if err != nil {
+			return err
+		}
+		return writeOutbox(ctx, tx, topicCustomerCreated, customer, now)
+	})
+	return customer, err
+}
+
+func (repository *customerRepositoryImpl) SearchByName(ctx context.Context, name string) ([]entity.Customer, error) {
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + " WHERE LOWER(name) LIKE LOWER(?) ORDER BY id"
+	return repository.findMany(ctx, script, "%"+escapeLike(name)+"%")
+}
+
+// Anonymize scrubs the identifying fields but keeps the row, so balance and audit history stay intact
+func (repository *customerRepositoryImpl) Anonymize(ctx context.Context, id string) error {
+	script := "UPDATE " + repository.Table + " SET name = 'REDACTED', email = NULL, birth_date = NULL WHERE id = ?"
+	result, err := repository.DB.ExecContext(ctx, script, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	// MySQL melaporkan 0 baris jika datanya sudah dianonimkan sebelumnya
+	exists, err := repository.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("id %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// Archive moves the customer from the live table to the archive in one transaction
+func (repository *customerRepositoryImpl) Archive(ctx context.Context, id string) error {
+	return WithTransaction(ctx, repository.DB, func(tx *sql.Tx) error {
+		script := "INSERT INTO " + repository.ArchiveTable + "(" + customerArchiveColumns + ") SELECT " + customerArchiveColumns +
+			" FROM " + repository.Table + " WHERE id = ? FOR UPDATE"
+		result, err := tx.ExecContext(ctx, script, id)
+		if isDuplicateKey(err) {
+			return fmt.Errorf("id %s already archived: %w", id, ErrDuplicateKey)
+		}
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("id %s: %w", id, ErrNotFound)
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM "+repository.Table+" WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		archived, err := repository.findByIdIn(ctx, tx, repository.ArchiveTable, id)
+		if err != nil {
+			return err
+		}
+		return writeOutbox(ctx, tx, topicCustomerArchived, archived, repository.Clock.Now())
+	})
+}
+
+func (repository *customerRepositoryImpl) Delete(ctx context.Context, id string) (int64, error) {
+	script := "DELETE FROM " + repository.Table + " WHERE id = ?"
+	if DryRun {
+		return dryRunCount(ctx, repository.DB, script, "SELECT COUNT(*) FROM "+repository.Table+" WHERE id = ?", id)
+	}
+
+	result, err := repository.DB.ExecContext(ctx, script, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ApplyDiff updates only the columns in changes, usually built with Diff, and bumps
+// updated_at. An empty diff does not touch the database.
+func (repository *customerRepositoryImpl) ApplyDiff(ctx context.Context, id string, changes map[string]any) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	columns := make([]string, 0, len(changes))
+	for column := range changes {
+		if !customerColumnSet[column] || column == "id" {
+			return fmt.Errorf("%w: unknown column %q", ErrInvalidArgument, column)
+		}
+		columns = append(columns, column)
+	}
+	// urutan kolom tetap supaya diff yang sama selalu menghasilkan statement yang sama
+	sort.Strings(columns)
+
+	sets := make([]string, 0, len(columns)+1)
+	args := make([]any, 0, len(columns)+2)
+	for _, column := range columns {
+		value := changes[column]
+		switch column {
+		case "name":
+			if name, ok := value.(string); ok {
+				value = NormalizeString(name, repository.NameRules...)
+			}
+		case "email":
+			if email, ok := value.(sql.NullString); ok && repository.EmptyStringAsNull && email.Valid && email.String == "" {
+				value = sql.NullString{}
+			}
+		}
+		quoted, _ := quoteIdent(column)
+		sets = append(sets, quoted+" = ?")
+		args = append(args, value)
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, repository.Clock.Now().UTC(), id)
+
+	script := "UPDATE " + repository.Table + " SET " + strings.Join(sets, ", ") + " WHERE id = ?"
+	result, err := repository.DB.ExecContext(ctx, script, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+	// 0 baris juga terjadi jika nilainya sudah sama, misalnya dengan Clock yang tetap
+	exists, err := repository.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("id %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// Touch sets updated_at to now for the given ids, unknown ids are not counted.
+// Only more than maxPlaceholders ids need a second statement.
+func (repository *customerRepositoryImpl) Touch(ctx context.Context, ids []string) (int64, error) {
+	now := repository.Clock.Now().UTC()
+	var total int64
+	for _, chunk := range Chunk(ids, maxPlaceholders-1) {
+		args := make([]any, 0, len(chunk)+1)
+		args = append(args, now)
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+
+		script := "UPDATE " + repository.Table + " SET updated_at = ? WHERE id IN (" + placeholders(len(chunk)) + ")"
+		result, err := repository.DB.ExecContext(ctx, script, args...)
+		if err != nil {
+			return total, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += affected
+	}
+	return total, nil
+}
+
+// PurgeDeletedBefore hard deletes the rows soft deleted before cutoff, rows with
+// a NULL deleted_at are never touched
+func (repository *customerRepositoryImpl) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	script := "DELETE FROM " + repository.Table + " WHERE deleted_at IS NOT NULL AND deleted_at < ?"
+	result, err := repository.DB.ExecContext(ctx, script, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (repository *customerRepositoryImpl) DeleteByIds(ctx context.Context, ids []string) (int64, error) {
+	var total int64
+	for _, chunk := range Chunk(ids, maxPlaceholders) {
+		args := make([]any, len(chunk))
+		for i, id := range chunk {
+			args[i] = id
+		}
+		where := " WHERE id IN (" + placeholders(len(chunk)) + ")"
+		script := "DELETE FROM " + repository.Table + where
+
+		var affected int64
+		var err error
+		if DryRun {
+			affected, err = dryRunCount(ctx, repository.DB, script, "SELECT COUNT(*) FROM "+repository.Table+where, args...)
+		} else {
+			var result sql.Result
+			result, err = repository.DB.ExecContext(ctx, script, args...)
+			if err == nil {
+				affected, err = result.RowsAffected()
+			}
+		}
+		if err != nil {
+			return total, err
+		}
+		total += affected
+	}
+	return total, nil
+}
+
+func (repository *customerRepositoryImpl) InsertIdempotent(ctx context.Context, key string, customer entity.Customer) error {
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := repository.Clock.Now()
+	script := "INSERT INTO idempotency_keys(`key`, created_at) VALUES (?,?)"
+	_, err = tx.ExecContext(ctx, script, key, now)
+	if isDuplicateKey(err) {
+		// request ini sudah pernah diproses
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	customer, err = repository.insert(ctx, tx, customer, now)
+	if err != nil {
+		return err
+	}
+	err = writeOutbox(ctx, tx, topicCustomerCreated, customer, now)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repository *customerRepositoryImpl) Count(ctx context.Context) (int, error) {
+	return QueryScalar[int](ctx, repository.DB, "SELECT COUNT(*) FROM "+repository.Table)
+}
+
+// CountDistinct counts the distinct non NULL values of a whitelisted column
+func (repository *customerRepositoryImpl) CountDistinct(ctx context.Context, column string) (int, error) {
+	if !customerColumnSet[column] {
+		return 0, fmt.Errorf("%w: unknown column %q", ErrInvalidArgument, column)
+	}
+	quoted, _ := quoteIdent(column)
+	return QueryScalar[int](ctx, repository.DB, "SELECT COUNT(DISTINCT "+quoted+") FROM "+repository.Table)
+}
+
+func (repository *customerRepositoryImpl) Exists(ctx context.Context, id string) (bool, error) {
+	return QueryScalar[bool](ctx, repository.DB, "SELECT EXISTS(SELECT 1 FROM "+repository.Table+" WHERE id = ?)", id)
+}
+
+func (repository *customerRepositoryImpl) TotalBalance(ctx context.Context) (int64, error) {
+	return QueryScalar[int64](ctx, repository.DB, "SELECT COALESCE(SUM(balance), 0) FROM "+repository.Table)
+}
+
+// BalancePercentile returns the nearest-rank percentile p of the balances. It uses
+// ORDER BY with OFFSET instead of window functions so it also runs on MySQL 5.7.
+func (repository *customerRepositoryImpl) BalancePercentile(ctx context.Context, p float64) (int32, error) {
+	if p < 0 || p > 100 {
+		return 0, fmt.Errorf("%w: percentile %v is outside 0-100", ErrInvalidArgument, p)
+	}
+
+	total, err := repository.Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, ErrNotFound
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+	script := "SELECT balance FROM " + repository.Table + " ORDER BY balance LIMIT 1 OFFSET ?"
+	return QueryScalar[int32](ctx, repository.DB, script, rank-1)
+}
+
+// BalanceHistory returns the audit events of a customer oldest first, each with the balance after it
+func (repository *customerRepositoryImpl) BalanceHistory(ctx context.Context, id string) ([]entity.BalanceEvent, error) {
+	script := "SELECT id, customer_id, amount, description, created_at FROM balance_audit WHERE customer_id = ? ORDER BY created_at, id"
+	rows, err := repository.DB.QueryContext(ctx, script, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []entity.BalanceEvent{}
+	var balance int64
+	for rows.Next() {
+		event := entity.BalanceEvent{}
+		err := rows.Scan(&event.Id, &event.CustomerId, &event.Amount, &event.Description, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		balance += int64(event.Amount)
+		event.Balance = balance
+		event.CreatedAt = event.CreatedAt.In(repository.Location)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (repository *customerRepositoryImpl) CountByMarried(ctx context.Context) (married int, single int, err error) {
+	// married disimpan sebagai tinyint(1), jadi SUM menghitung baris yang bernilai 1 / 0
+	script := "SELECT COALESCE(SUM(married = 1), 0), COALESCE(SUM(married = 0), 0) FROM " + repository.Table
+	err = repository.DB.QueryRowContext(ctx, script).Scan(&married, &single)
+	return married, single, err
+}
+
+func (repository *customerRepositoryImpl) writeBatch(ctx context.Context, customers []entity.Customer, suffix string) error {
+	now := repository.Clock.Now()
+	return WithTransaction(ctx, repository.DB, func(tx *sql.Tx) error {
+		for _, chunk := range Chunk(customers, customerBatchSize) {
+			// berhenti di antara chunk, WithTransaction me-rollback chunk yang sudah masuk
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			values := make([]string, len(chunk))
+			args := make([]any, 0, len(chunk)*9)
+			for i, customer := range chunk {
+				customer = repository.prepareInsert(customer, now)
+				values[i] = customerPlaceholders
+				args = append(args, customerArgs(customer)...)
+			}
+
+			script := "INSERT INTO " + repository.Table + "(" + customerColumns + ") VALUES " + strings.Join(values, ",") + suffix
+			_, err := tx.ExecContext(ctx, script, args...)
+			if isDuplicateKey(err) {
+				return ErrDuplicateKey
+			}
+			if err != nil {
+				return err
+			}
+			afterBatchChunk()
+		}
+		return nil
+	})
+}
+
+// diganti di test untuk membatalkan context di tengah batch
+var afterBatchChunk = func() {}
+
+func (repository *customerRepositoryImpl) InsertBatch(ctx context.Context, customers []entity.Customer) error {
+	if len(customers) == 0 {
+		return nil
+	}
+	return repository.writeBatch(ctx, customers, "")
+}
+
+// jumlah customer yang dikumpulkan dari channel sebelum di-flush
+const channelBatchSize = 100
+
+// InsertFromChannel drains in until it is closed, inserting every channelBatchSize customers
+// with InsertBatch. When ctx is cancelled the batches already flushed stay committed and the
+// buffered customers that were not flushed yet are dropped.
+func (repository *customerRepositoryImpl) InsertFromChannel(ctx context.Context, in <-chan entity.Customer) (int, error) {
+	total := 0
+	buffer := make([]entity.Customer, 0, channelBatchSize)
+	flush := func() error {
+		err := repository.InsertBatch(ctx, buffer)
+		if err != nil {
+			return err
+		}
+		total += len(buffer)
+		buffer = buffer[:0]
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case customer, ok := <-in:
+			if !ok {
+				return total, flush()
+			}
+			buffer = append(buffer, customer)
+			if len(buffer) == channelBatchSize {
+				if err := flush(); err != nil {
+					return total, err
+				}
+			}
+		}
+	}
+}
+
+func (repository *customerRepositoryImpl) UpsertBatch(ctx context.Context, customers []entity.Customer) error {
+	if len(customers) == 0 {
+		return nil
+	}
+	return repository.writeBatch(ctx, customers, " ON DUPLICATE KEY UPDATE name = VALUES(name), email = VALUES(email),"+
+		" balance = VALUES(balance), rating = VALUES(rating), birth_date = VALUES(birth_date), married = VALUES(married),"+
+		" status = VALUES(status)")
+}
+
+func (repository *customerRepositoryImpl) FindOrCreate(ctx context.Context, customer entity.Customer) (entity.Customer, bool, error) {
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return customer, false, err
+	}
+	defer tx.Rollback()
+
+	existing, err := repository.findById(ctx, tx, customer.Id)
+	if err == nil {
+		return existing, false, tx.Commit()
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return customer, false, err
+	}
+
+	now := repository.Clock.Now()
+	created, err := repository.insert(ctx, tx, customer, now)
+	if errors.Is(err, ErrDuplicateKey) {
+		// transaksi lain sudah lebih dulu insert, baca ulang di luar transaksi ini
+		tx.Rollback()
+		existing, err := repository.FindById(ctx, customer.Id)
+		return existing, false, err
+	}
+	if err != nil {
+		return customer, false, err
+	}
+	err = writeOutbox(ctx, tx, topicCustomerCreated, created, now)
+	if err != nil {
+		return customer, false, err
+	}
+	return created, true, tx.Commit()
+}
+
+func (repository *customerRepositoryImpl) FindDuplicateNames(ctx context.Context) (map[string]int, error) {
+	script := "SELECT name, COUNT(*) FROM " + repository.Table + " GROUP BY name HAVING COUNT(*) > 1"
+	duplicates := map[string]int{}
+	err := Query(ctx, repository.DB, script, func(rows *sql.Rows) error {
+		var name string
+		var total int
+		err := rows.Scan(&name, &total)
+		if err != nil {
+			return err
+		}
+		duplicates[name] = total
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
+
+// Update returns the number of rows changed. updated_at is bumped on every call,
+// so an existing customer counts as changed even when its other values stay the same.
+func (repository *customerRepositoryImpl) Update(ctx context.Context, customer entity.Customer) (int64, error) {
+	return updateCustomer(ctx, repository.DB, repository.Table, repository.normalize(customer), repository.Clock.Now())
+}
+
+func updateCustomer(ctx context.Context, db DBTX, table string, customer entity.Customer, now time.Time) (int64, error) {
+	if customer.Status == "" {
+		customer.Status = entity.StatusActive
+	}
+
+	script := "UPDATE " + table + " SET name = ?, email = ?, balance = ?, rating = ?, birth_date = ?, married = ?, status = ?, updated_at = ? WHERE id = ?"
+	result, err := db.ExecContext(ctx, script, customer.Name, customer.Email, customer.Balance,
+		customer.Rating, customer.BirthDate, customer.Married, customer.Status, now.UTC(), customer.Id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// kolom yang diubah UpdateBatch, 2 placeholder per kolom untuk CASE ditambah 1 untuk IN,
+// ditambah 1 placeholder untuk updated_at per statement
+var updateBatchColumns = []string{"name", "email", "balance", "rating", "birth_date", "married", "status"}
+
+const updateBatchSize = (maxPlaceholders - 1) / 15
+
+// UpdateBatch updates many customers with one CASE based UPDATE per chunk. Only rows
+// that already exist are updated, customers with an unknown id are ignored.
+func (repository *customerRepositoryImpl) UpdateBatch(ctx context.Context, customers []entity.Customer) error {
+	if len(customers) == 0 {
+		return nil
+	}
+
+	now := repository.Clock.Now().UTC()
+	return WithTransaction(ctx, repository.DB, func(tx *sql.Tx) error {
+		for _, chunk := range Chunk(customers, updateBatchSize) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			values := make([][]any, len(chunk))
+			for i, customer := range chunk {
+				customer = repository.normalize(customer)
+				if customer.Status == "" {
+					customer.Status = entity.StatusActive
+				}
+				values[i] = []any{customer.Name, customer.Email, customer.Balance,
+					customer.Rating, customer.BirthDate, customer.Married, customer.Status}
+			}
+
+			sets := make([]string, len(updateBatchColumns))
+			args := []any{now}
+			for i, column := range updateBatchColumns {
+				sets[i] = column + " = CASE id" + strings.Repeat(" WHEN ? THEN ?", len(chunk)) + " END"
+				for j, customer := range chunk {
+					args = append(args, customer.Id, values[j][i])
+				}
+			}
+			ids := make([]any, len(chunk))
+			for i, customer := range chunk {
+				ids[i] = customer.Id
+			}
+			args = append(args, ids...)
+
+			script := "UPDATE " + repository.Table + " SET updated_at = ?, " + strings.Join(sets, ", ") + " WHERE id IN (" + placeholders(len(chunk)) + ")"
+			_, err := tx.ExecContext(ctx, script, args...)
+			if err != nil {
+				return err
+			}
+
+			// event hanya untuk customer yang memang ada, id yang tidak dikenal dilewati
+			script = "SELECT " + customerColumns + " FROM " + repository.Table + " WHERE id IN (" + placeholders(len(chunk)) + ")"
+			updated, err := Collect(ctx, tx, script, repository.scanCustomer, ids...)
+			if err != nil {
+				return err
+			}
+			for _, customer := range updated {
+				err := writeOutbox(ctx, tx, topicCustomerUpdated, customer, now)
+				if err != nil {
+					return err
+				}
+			}
+			afterBatchChunk()
+		}
+		return nil
+	})
+}
+
+// UpdateAndFetch updates the customer and reads it back in the same transaction.
+// The updated row stays locked until commit, so other writers can not slip in between.
+func (repository *customerRepositoryImpl) UpdateAndFetch(ctx context.Context, customer entity.Customer) (entity.Customer, error) {
+	var updated entity.Customer
+	now := repository.Clock.Now()
+	err := WithTransaction(ctx, repository.DB, func(tx *sql.Tx) error {
+		_, err := updateCustomer(ctx, tx, repository.Table, repository.normalize(customer), now)
+		if err != nil {
+			return err
+		}
+		updated, err = repository.findById(ctx, tx, customer.Id)
+		if err != nil {
+			return err
+		}
+		return writeOutbox(ctx, tx, topicCustomerUpdated, updated, now)
+	})
+	return updated, err
+}
+
+func (repository *customerRepositoryImpl) UpdateMarriedForAll(ctx context.Context, married bool) (int64, error) {
+	script := "UPDATE " + repository.Table + " SET married = ?"
+	result, err := repository.DB.ExecContext(ctx, script, married)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (repository *customerRepositoryImpl) FindByIdColumns(ctx context.Context, id string, columns []string) (entity.Customer, error) {
+	customer := entity.Customer{}
+	if len(columns) == 0 {
+		return customer, fmt.Errorf("%w: no columns requested", ErrInvalidArgument)
+	}
+
+	fields := customerFields(&customer)
+	quoted := make([]string, len(columns))
+	targets := make([]any, len(columns))
+	for i, column := range columns {
+		target, ok := fields[column]
+		if !ok {
+			return customer, fmt.Errorf("%w: unknown column %q", ErrInvalidArgument, column)
+		}
+		quoted[i], _ = quoteIdent(column)
+		targets[i] = target
+	}
+
+	script := "SELECT " + strings.Join(quoted, ", ") + " FROM " + repository.Table + " WHERE id = ? LIMIT 1"
+	err := repository.DB.QueryRowContext(ctx, script, id).Scan(targets...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Customer{}, fmt.Errorf("id %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	if !customer.CreatedAt.IsZero() {
+		customer.CreatedAt = customer.CreatedAt.In(repository.Location)
+	}
+	return customer, nil
+}
+
+func findFirstScript(table string, limit int) string {
+	return "SELECT " + customerColumns + " FROM " + table + " ORDER BY id LIMIT " + strconv.Itoa(limit)
+}
+
+// FindFirst returns the first page without paying for an OFFSET. Following pages
+// should continue with keyset pagination (WHERE id > last id) instead of OFFSET.
+func (repository *customerRepositoryImpl) FindFirst(ctx context.Context, limit int) ([]entity.Customer, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrInvalidArgument)
+	}
+
+	return Collect(ctx, repository.DB, findFirstScript(repository.Table, limit), repository.scanCustomer)
+}
+
+// ForEachPage walks the whole table by id with keyset pagination, one short query per page,
+// and stops at the first error returned by fn
+func (repository *customerRepositoryImpl) ForEachPage(ctx context.Context, pageSize int, fn func([]entity.Customer) error) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("%w: page size must be positive", ErrInvalidArgument)
+	}
+
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + " WHERE id > ? ORDER BY id LIMIT ?"
+	lastId := ""
+	for {
+		customers, err := Collect(ctx, repository.DB, script, repository.scanCustomer, lastId, pageSize)
+		if err != nil {
+			return err
+		}
+		if len(customers) == 0 {
+			return nil
+		}
+
+		err = fn(customers)
+		if err != nil {
+			return err
+		}
+		if len(customers) < pageSize {
+			return nil
+		}
+		lastId = customers[len(customers)-1].Id
+	}
+}
+
+func (repository *customerRepositoryImpl) CloneCustomer(ctx context.Context, srcId string, newId string) (entity.Customer, error) {
+	var clone entity.Customer
+	err := WithTransaction(ctx, repository.DB, func(tx *sql.Tx) error {
+		source, err := repository.findById(ctx, tx, srcId)
+		if err != nil {
+			return err
+		}
+
+		source.Id = newId
+		source.CreatedAt = repository.Clock.Now()
+		clone, err = repository.insert(ctx, tx, source, source.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return writeOutbox(ctx, tx, topicCustomerCreated, clone, source.CreatedAt)
+	})
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	return clone, nil
+}
+
+// FindByIdsChunked runs one IN query per chunk of ids, missing ids are simply absent
+func (repository *customerRepositoryImpl) FindByIdsChunked(ctx context.Context, ids []string, chunkSize int) ([]entity.Customer, error) {
+	if chunkSize <= 0 || chunkSize > maxPlaceholders {
+		chunkSize = maxPlaceholders
+	}
+
+	// id yang sama cukup dicari sekali
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	var customers []entity.Customer
+	for _, chunk := range Chunk(unique, chunkSize) {
+		args := make([]any, len(chunk))
+		for i, id := range chunk {
+			args[i] = id
+		}
+
+		script := "SELECT " + customerColumns + " FROM " + repository.Table + " WHERE id IN (" + placeholders(len(chunk)) + ") ORDER BY id"
+		found, err := Collect(ctx, repository.DB, script, repository.scanCustomer, args...)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, found...)
+	}
+	return customers, nil
+}
+
+// FindByIdsMap is FindByIdsChunked keyed by id, ids that do not exist are absent from the map
+func (repository *customerRepositoryImpl) FindByIdsMap(ctx context.Context, ids []string) (map[string]entity.Customer, error) {
+	customers, err := repository.FindByIdsChunked(ctx, ids, maxPlaceholders)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]entity.Customer, len(customers))
+	for _, customer := range customers {
+		byId[customer.Id] = customer
+	}
+	return byId, nil
+}
+
+// ExportNDJSON writes one customer per line and flushes w after every row when it
+// supports flushing, so huge tables can be piped without loading them in memory
+func (repository *customerRepositoryImpl) ExportNDJSON(ctx context.Context, w io.Writer) error {
+	return repository.StreamExport(ctx, w, "ndjson")
+}
+
+// StreamExport writes every customer to w as csv, json or ndjson while the rows are
+// scanned, flushing after each row and stopping as soon as ctx is done
+func (repository *customerRepositoryImpl) StreamExport(ctx context.Context, w io.Writer, format string) error {
+	exporter, err := newExporter(w, format)
+	if err != nil {
+		return err
+	}
+
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+customerColumns+" FROM "+repository.Table+" ORDER BY id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	err = exporter.Begin()
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		customer, err := repository.scanCustomer(rows)
+		if err != nil {
+			return err
+		}
+		err = exporter.Write(customer)
+		if err != nil {
+			return err
+		}
+		err = flush(w)
+		if err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	err = exporter.End()
+	if err != nil {
+		return err
+	}
+	return flush(w)
+}
+
+// MergeByEmail keeps the earliest created customer with the email, moves the
+// balances of the others onto it and deletes them. It returns the surviving id.
+// mergeByEmailScript locks every customer sharing an email, the oldest one first
+func mergeByEmailScript(table string) string {
+	return "SELECT id, balance FROM " + table + " WHERE email = ? ORDER BY created_at, id FOR UPDATE"
+}
+
+func (repository *customerRepositoryImpl) MergeByEmail(ctx context.Context, email string) (string, error) {
+	var survivor string
+	err := WithTransaction(ctx, repository.DB, func(tx *sql.Tx) error {
+		var ids []string
+		var total int64
+		err := Query(ctx, tx, mergeByEmailScript(repository.Table), func(rows *sql.Rows) error {
+			var id string
+			var balance int64
+			err := rows.Scan(&id, &balance)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			total += balance
+			return nil
+		}, email)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return fmt.Errorf("email %s: %w", email, ErrNotFound)
+		}
+		survivor = ids[0]
+		if len(ids) == 1 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE "+repository.Table+" SET balance = ? WHERE id = ?", total, survivor)
+		if err != nil {
+			return err
+		}
+
+		duplicates := ids[1:]
+		args := make([]any, len(duplicates))
+		for i, id := range duplicates {
+			args[i] = id
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM "+repository.Table+" WHERE id IN ("+placeholders(len(duplicates))+")", args...)
+		if err != nil {
+			return err
+		}
+		event := customerMergedEvent{Email: email, Survivor: survivor, Merged: duplicates, Balance: total}
+		return writeOutbox(ctx, tx, topicCustomerMerged, event, repository.Clock.Now())
+	})
+	if err != nil {
+		return "", err
+	}
+	return survivor, nil
+}
+
+// SampleCustomers returns n pseudo random customers. The order only depends on the
+// ids and seed, so the same seed always gives the same sample.
+func (repository *customerRepositoryImpl) SampleCustomers(ctx context.Context, n int, seed int64) ([]entity.Customer, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: n must be positive", ErrInvalidArgument)
+	}
+
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + " ORDER BY MD5(CONCAT(id, ?)), id LIMIT ?"
+	return Collect(ctx, repository.DB, script, repository.scanCustomer, strconv.FormatInt(seed, 10), n)
+}
+
+func recentCustomersScript(table string) string {
+	return "SELECT " + customerColumns + " FROM " + table + " ORDER BY created_at DESC, id DESC LIMIT ?"
+}
+
+// RecentCustomers returns the n newest customers, walking idx_customer_created_at backwards
+func (repository *customerRepositoryImpl) RecentCustomers(ctx context.Context, n int) ([]entity.Customer, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: n must be positive", ErrInvalidArgument)
+	}
+
+	return Collect(ctx, repository.DB, recentCustomersScript(repository.Table), repository.scanCustomer, n)
+}
+
+// FindByRatingBetween pages through the customers rated from min to max, both inclusive
+func (repository *customerRepositoryImpl) FindByRatingBetween(ctx context.Context, min float64, max float64, limit int, offset int) ([]entity.Customer, error) {
+	if min > max {
+		return nil, fmt.Errorf("%w: min rating %v is above max %v", ErrInvalidArgument, min, max)
+	}
+
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + " WHERE rating BETWEEN ? AND ? ORDER BY rating, id LIMIT ? OFFSET ?"
+	return Collect(ctx, repository.DB, script, repository.scanCustomer, min, max, limit, offset)
+}
+
+func (repository *customerRepositoryImpl) FindMissingEmail(ctx context.Context, limit int, offset int) ([]entity.Customer, error) {
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + " WHERE email IS NULL OR email = '' ORDER BY id LIMIT ? OFFSET ?"
+	return Collect(ctx, repository.DB, script, repository.scanCustomer, limit, offset)
+}
+
+// AddBalanceWhereRatingAbove adjusts every qualifying customer with one set-based UPDATE
+func (repository *customerRepositoryImpl) AddBalanceWhereRatingAbove(ctx context.Context, minRating float64, delta int32) (int64, error) {
+	where, whereArgs := newCondition(customerColumnSet).Gt("rating", minRating).Build()
+	script := "UPDATE " + repository.Table + " SET balance = balance + ? " + where
+	result, err := repository.DB.ExecContext(ctx, script, append([]any{delta}, whereArgs...)...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// FindByIdForUpdate locks the row until tx ends. It is meant to be used inside a
+// transaction, in autocommit mode the lock is taken and released immediately.
+func (repository *customerRepositoryImpl) FindByIdForUpdate(ctx context.Context, tx *sql.Tx, id string) (entity.Customer, error) {
+	script := "SELECT " + customerColumns + " FROM " + repository.Table + " WHERE id = ? LIMIT 1 FOR UPDATE"
+	rows, err := tx.QueryContext(ctx, script, id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return repository.scanCustomer(rows)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.Customer{}, err
+	}
+	return entity.Customer{}, fmt.Errorf("id %s: %w", id, ErrNotFound)
+}
@@ -0,0 +1,2057 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (clock fixedClock) Now() time.Time {
+	return clock.now
+}
+
+func countBalanceAudit(t *testing.T, db *sql.DB, customerId string) int {
+	t.Helper()
+	total, err := QueryScalar[int](context.Background(), db, "SELECT COUNT(*) FROM balance_audit WHERE customer_id = ?", customerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return total
+}
+
+func seedCustomers(t testing.TB, db *sql.DB, customers ...entity.Customer) {
+	t.Helper()
+	customerRepository := NewCustomerRepository(db)
+	for _, customer := range customers {
+		_, err := customerRepository.Insert(context.Background(), customer)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func customerIds(customers []entity.Customer) []string {
+	var ids []string
+	for _, customer := range customers {
+		ids = append(ids, customer.Id)
+	}
+	return ids
+}
+
+func TestCustomerFindEmailIsNull(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya", Email: sql.NullString{String: "arya@test.com", Valid: true}},
+		entity.Customer{Id: "nadia", Name: "Nadia"},
+	)
+
+	customerRepository := NewCustomerRepository(db)
+	isNull := true
+	customers, err := customerRepository.Find(context.Background(), CustomerFilter{EmailIsNull: &isNull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 2 || ids[0] != "nadia" || ids[1] != "nafis" {
+		t.Fatalf("expected [nadia nafis], got %v", ids)
+	}
+
+	isNull = false
+	customers, err = customerRepository.Find(context.Background(), CustomerFilter{EmailIsNull: &isNull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = customerIds(customers)
+	if len(ids) != 1 || ids[0] != "arya" {
+		t.Fatalf("expected [arya], got %v", ids)
+	}
+}
+
+func TestCustomerFindEmailIsNullWithNameLike(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "nadia", Name: "Nadia", Email: sql.NullString{String: "nadia@test.com", Valid: true}},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+
+	customerRepository := NewCustomerRepository(db)
+	isNull := true
+	customers, err := customerRepository.Find(context.Background(), CustomerFilter{NameLike: "Na", EmailIsNull: &isNull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 1 || ids[0] != "nafis" {
+		t.Fatalf("expected [nafis], got %v", ids)
+	}
+}
+
+func TestCustomerOnboard(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	customer, err := customerRepository.Onboard(ctx, entity.Customer{Id: "nafis", Name: "Nafis"}, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Balance != 500 {
+		t.Fatalf("expected balance 500, got %d", customer.Balance)
+	}
+
+	stored, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Balance != 500 {
+		t.Fatalf("expected stored balance 500, got %d", stored.Balance)
+	}
+	if total := countBalanceAudit(t, db, "nafis"); total != 1 {
+		t.Fatalf("expected 1 audit row, got %d", total)
+	}
+}
+
+func TestCustomerOnboardDuplicate(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	_, err := customerRepository.Onboard(ctx, entity.Customer{Id: "nafis", Name: "Nafis Baru"}, 500)
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+
+	stored, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "Nafis" || stored.Balance != 0 {
+		t.Fatalf("existing customer was modified: %+v", stored)
+	}
+	if total := countBalanceAudit(t, db, "nafis"); total != 0 {
+		t.Fatalf("expected no audit rows, got %d", total)
+	}
+}
+
+func TestCustomerSearchByNameIgnoreCase(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "arya", Name: "NAFIS ARYA"},
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+
+	customerRepository := NewCustomerRepository(db)
+	customers, err := customerRepository.SearchByName(context.Background(), "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 2 || ids[0] != "arya" || ids[1] != "nafis" {
+		t.Fatalf("expected [arya nafis], got %v", ids)
+	}
+}
+
+func TestCustomerSearchByNameEscapesWildcard(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "persen", Name: "Diskon 100% Nafis"},
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+	)
+
+	customerRepository := NewCustomerRepository(db)
+	customers, err := customerRepository.SearchByName(context.Background(), "100% NAFIS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 1 || ids[0] != "persen" {
+		t.Fatalf("expected [persen], got %v", ids)
+	}
+
+	customers, err = customerRepository.SearchByName(context.Background(), "%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := customerIds(customers); len(ids) != 1 || ids[0] != "persen" {
+		t.Fatalf("expected %% to match literally, got %v", ids)
+	}
+}
+
+func FuzzSearchByName(f *testing.F) {
+	db, _ := belajar_golang_database.SetupTestDB(f)
+	seedCustomers(f, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+	for _, payload := range []string{
+		"'; DROP TABLE customer; --",
+		"' OR '1'='1",
+		"admin'#",
+		"\\'; DELETE FROM customer; --",
+		"%_\\",
+		"\x00",
+	} {
+		f.Add(payload)
+	}
+
+	customerRepository := NewCustomerRepository(db)
+	f.Fuzz(func(t *testing.T, name string) {
+		if !utf8.ValidString(name) {
+			// bukan utf8mb4, MySQL menolaknya sebelum query dijalankan
+			t.Skip()
+		}
+		ctx := context.Background()
+		if _, err := customerRepository.SearchByName(ctx, name); err != nil {
+			t.Fatalf("SearchByName(%q): %v", name, err)
+		}
+		total, err := QueryScalar[int](ctx, db, "SELECT COUNT(*) FROM customer")
+		if err != nil {
+			t.Fatalf("customer table is gone after SearchByName(%q): %v", name, err)
+		}
+		if total != 3 {
+			t.Fatalf("expected 3 customers after SearchByName(%q), got %d", name, total)
+		}
+	})
+}
+
+func TestCustomerDeleteDryRun(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	DryRun = true
+	defer func() { DryRun = false }()
+
+	affected, err := customerRepository.Delete(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 1 {
+		t.Fatalf("expected 1 candidate row, got %d", affected)
+	}
+	if _, err := customerRepository.FindById(ctx, "nafis"); err != nil {
+		t.Fatalf("dry run deleted the row: %v", err)
+	}
+
+	DryRun = false
+	affected, err = customerRepository.Delete(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 1 {
+		t.Fatalf("expected 1 deleted row, got %d", affected)
+	}
+	if _, err := customerRepository.FindById(ctx, "nafis"); err == nil {
+		t.Fatal("expected row to be deleted")
+	}
+}
+
+func TestCustomerDeleteByIdsDryRun(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	DryRun = true
+	defer func() { DryRun = false }()
+
+	affected, err := customerRepository.DeleteByIds(ctx, []string{"nafis", "arya", "budi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 2 {
+		t.Fatalf("expected 2 candidate rows, got %d", affected)
+	}
+
+	customers, err := customerRepository.FindAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 2 {
+		t.Fatalf("dry run deleted rows, %d left", len(customers))
+	}
+}
+
+func TestTruncateTablesDryRun(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	ctx := context.Background()
+
+	DryRun = true
+	defer func() { DryRun = false }()
+
+	affected, err := TruncateTables(ctx, db, "customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 1 {
+		t.Fatalf("expected 1 candidate row, got %d", affected)
+	}
+	if _, err := NewCustomerRepository(db).FindById(ctx, "nafis"); err != nil {
+		t.Fatalf("dry run truncated the table: %v", err)
+	}
+
+	if _, err := TruncateTables(ctx, db, "customer; DROP TABLE user"); err == nil {
+		t.Fatal("expected unknown table to be rejected")
+	}
+}
+
+func TestTruncateTablesWithConfig(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	ctx := context.Background()
+	t.Cleanup(func() {
+		for _, table := range []string{"app_customer", "app_customer_archive", "app_user"} {
+			db.Exec("DROP TABLE IF EXISTS " + table)
+		}
+	})
+
+	config := belajar_golang_database.DefaultConfig()
+	config.TablePrefix = "app_"
+	if err := belajar_golang_database.MigrateWithConfig(ctx, db, config); err != nil {
+		t.Fatal(err)
+	}
+	prefixedRepository := NewCustomerRepositoryWithConfig(db, config)
+	if _, err := prefixedRepository.Insert(ctx, entity.Customer{Id: "arya", Name: "Arya"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := TruncateTablesWithConfig(ctx, db, config, "customer"); err != nil {
+		t.Fatal(err)
+	}
+	if total, _ := prefixedRepository.Count(ctx); total != 0 {
+		t.Fatalf("expected app_customer to be truncated, got %d rows", total)
+	}
+	if total, _ := NewCustomerRepository(db).Count(ctx); total != 1 {
+		t.Fatalf("expected the unprefixed customer table untouched, got %d rows", total)
+	}
+}
+
+func TestCustomerInsertIdempotent(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	err := customerRepository.InsertIdempotent(ctx, "request-1", entity.Customer{Id: "nafis", Name: "Nafis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = customerRepository.InsertIdempotent(ctx, "request-1", entity.Customer{Id: "nafis", Name: "Nafis"})
+	if err != nil {
+		t.Fatalf("expected retried request to return nil, got %v", err)
+	}
+
+	customers, err := customerRepository.FindAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 1 {
+		t.Fatalf("expected exactly 1 customer, got %d", len(customers))
+	}
+}
+
+func TestCustomerCountByMarried(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis", Married: true},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi", Married: true},
+		entity.Customer{Id: "nadia", Name: "Nadia"},
+		entity.Customer{Id: "joko", Name: "Joko"},
+	)
+
+	married, single, err := NewCustomerRepository(db).CountByMarried(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if married != 2 || single != 3 {
+		t.Fatalf("expected 2 married and 3 single, got %d and %d", married, single)
+	}
+}
+
+func TestCustomerCountByMarriedAllSingle(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "nadia", Name: "Nadia"},
+	)
+
+	married, single, err := NewCustomerRepository(db).CountByMarried(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if married != 0 || single != 2 {
+		t.Fatalf("expected 0 married and 2 single, got %d and %d", married, single)
+	}
+}
+
+func TestCustomerInsertMultibyteName(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	name := "Nafis 😀 ナフィス Ärya"
+	_, err := customerRepository.Insert(ctx, entity.Customer{Id: "nafis", Name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != name {
+		t.Fatalf("expected %q, got %q", name, customer.Name)
+	}
+}
+
+func TestCustomerInsertBatch(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	customers := make([]entity.Customer, customerBatchSize+10)
+	for i := range customers {
+		customers[i] = entity.Customer{Id: "batch" + strconv.Itoa(i), Name: "Batch " + strconv.Itoa(i)}
+	}
+	err := customerRepository.InsertBatch(ctx, customers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := customerRepository.Count(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != len(customers) {
+		t.Fatalf("expected %d customers, got %d", len(customers), total)
+	}
+}
+
+func TestCustomerUpsertBatch(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	err := customerRepository.UpsertBatch(ctx, []entity.Customer{
+		{Id: "nafis", Name: "Nafis Arya", Balance: 100},
+		{Id: "budi", Name: "Budi"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != "Nafis Arya" || customer.Balance != 100 {
+		t.Fatalf("expected nafis to be updated, got %+v", customer)
+	}
+	if total, _ := customerRepository.Count(ctx); total != 2 {
+		t.Fatalf("expected 2 customers, got %d", total)
+	}
+}
+
+func TestCustomerFindOrCreateFound(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+
+	customer, created, err := NewCustomerRepository(db).FindOrCreate(context.Background(), entity.Customer{Id: "nafis", Name: "Nafis Baru"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || customer.Name != "Nafis" {
+		t.Fatalf("expected existing customer, got created=%v %+v", created, customer)
+	}
+}
+
+func TestCustomerFindOrCreateCreated(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+
+	customer, created, err := customerRepository.FindOrCreate(context.Background(), entity.Customer{Id: "nafis", Name: "Nafis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || customer.Name != "Nafis" {
+		t.Fatalf("expected new customer, got created=%v %+v", created, customer)
+	}
+	if exists, _ := customerRepository.Exists(context.Background(), "nafis"); !exists {
+		t.Fatal("expected customer to be stored")
+	}
+}
+
+func TestCustomerFindOrCreateConcurrent(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+
+	var group sync.WaitGroup
+	results := make([]bool, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		group.Add(1)
+		go func(i int) {
+			defer group.Done()
+			_, results[i], errs[i] = customerRepository.FindOrCreate(context.Background(), entity.Customer{Id: "nafis", Name: "Nafis"})
+		}(i)
+	}
+	group.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if results[0] == results[1] {
+		t.Fatalf("expected exactly one goroutine to create, got %v", results)
+	}
+}
+
+func TestCustomerCreatedAtTimeZone(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	createdAt := time.Date(2024, time.March, 10, 9, 30, 0, 0, newYork)
+
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+	_, err = customerRepository.Insert(ctx, entity.Customer{Id: "nafis", Name: "Nafis", CreatedAt: createdAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !customer.CreatedAt.Equal(createdAt) {
+		t.Fatalf("expected instant %s, got %s", createdAt, customer.CreatedAt)
+	}
+	if customer.CreatedAt.Location() != time.UTC {
+		t.Fatalf("expected UTC by default, got %s", customer.CreatedAt.Location())
+	}
+
+	config := belajar_golang_database.DefaultConfig()
+	config.Location = newYork
+	customer, err = NewCustomerRepositoryWithConfig(db, config).FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !customer.CreatedAt.Equal(createdAt) || customer.CreatedAt.Location() != newYork {
+		t.Fatalf("expected %s in New York, got %s", createdAt, customer.CreatedAt)
+	}
+}
+
+func TestCustomerFindDuplicateNames(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis1", Name: "Nafis"},
+		entity.Customer{Id: "nafis2", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+
+	duplicates, err := NewCustomerRepository(db).FindDuplicateNames(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates) != 1 || duplicates["Nafis"] != 2 {
+		t.Fatalf("expected map[Nafis:2], got %v", duplicates)
+	}
+}
+
+func TestCustomerUpdateMarriedForAll(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis", Married: true},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+
+	// hanya baris yang nilainya benar-benar berubah yang dihitung
+	affected, err := NewCustomerRepository(db).UpdateMarriedForAll(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 2 {
+		t.Fatalf("expected 2 changed rows, got %d", affected)
+	}
+
+	married, single, err := NewCustomerRepository(db).CountByMarried(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if married != 3 || single != 0 {
+		t.Fatalf("expected everyone married, got %d married %d single", married, single)
+	}
+}
+
+func TestCustomerUpdate(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	affected, err := customerRepository.Update(ctx, entity.Customer{Id: "nafis", Name: "Nafis Arya"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 1 {
+		t.Fatalf("expected 1 changed row, got %d", affected)
+	}
+
+	affected, err = customerRepository.Update(ctx, entity.Customer{Id: "budi", Name: "Budi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 0 {
+		t.Fatalf("expected 0 changed rows for a missing id, got %d", affected)
+	}
+}
+
+func TestCustomerFindAllMaxRows(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+
+	config := belajar_golang_database.DefaultConfig()
+	config.MaxRows = 2
+	_, err := NewCustomerRepositoryWithConfig(db, config).FindAll(context.Background())
+	if !errors.Is(err, ErrResultTooLarge) {
+		t.Fatalf("expected ErrResultTooLarge, got %v", err)
+	}
+
+	config.MaxRows = 3
+	customers, err := NewCustomerRepositoryWithConfig(db, config).FindAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 3 {
+		t.Fatalf("expected all 3 customers, got %d", len(customers))
+	}
+}
+
+func TestCustomerFindByIdColumns(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis", Balance: 1000, Rating: 90.5})
+
+	customer, err := NewCustomerRepository(db).FindByIdColumns(context.Background(), "nafis", []string{"id", "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Id != "nafis" || customer.Name != "Nafis" {
+		t.Fatalf("expected id and name to be populated, got %+v", customer)
+	}
+	if customer.Balance != 0 || customer.Rating != 0 || !customer.CreatedAt.IsZero() {
+		t.Fatalf("expected unrequested columns to stay zero, got %+v", customer)
+	}
+}
+
+func TestCustomerFindByIdColumnsUnknownColumn(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+
+	_, err := NewCustomerRepository(db).FindByIdColumns(context.Background(), "nafis", []string{"id", "password"})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestFindFirstScript(t *testing.T) {
+	script := findFirstScript("customer", 10)
+	if !strings.Contains(script, "LIMIT 10") || strings.Contains(script, "OFFSET") {
+		t.Fatalf("expected LIMIT without OFFSET, got %s", script)
+	}
+}
+
+func TestCustomerFindFirst(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+	customerRepository := NewCustomerRepository(db)
+
+	customers, err := customerRepository.FindFirst(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 2 || ids[0] != "arya" || ids[1] != "budi" {
+		t.Fatalf("expected [arya budi], got %v", ids)
+	}
+
+	_, err = customerRepository.FindFirst(context.Background(), 0)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCustomerUpdateAndFetch(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	updated, err := customerRepository.UpdateAndFetch(ctx, entity.Customer{Id: "nafis", Name: "Nafis Arya", Balance: 250, Married: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != stored.Name || updated.Balance != stored.Balance || updated.Married != stored.Married ||
+		!updated.CreatedAt.Equal(stored.CreatedAt) {
+		t.Fatalf("expected returned customer %+v to match stored %+v", updated, stored)
+	}
+
+	_, err = customerRepository.UpdateAndFetch(ctx, entity.Customer{Id: "budi", Name: "Budi"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCustomerUpdateAndFetchConcurrent(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	var group sync.WaitGroup
+	group.Add(1)
+	go func() {
+		defer group.Done()
+		for i := 0; i < 20; i++ {
+			if _, err := customerRepository.Update(ctx, entity.Customer{Id: "nafis", Name: "Writer", Balance: 1}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		updated, err := customerRepository.UpdateAndFetch(ctx, entity.Customer{Id: "nafis", Name: "Fetcher", Balance: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated.Name != "Fetcher" || updated.Balance != 2 {
+			t.Fatalf("concurrent write leaked into the returned customer: %+v", updated)
+		}
+	}
+	group.Wait()
+}
+
+func TestCustomerClone(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	createdAt := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	seedCustomers(t, db, entity.Customer{
+		Id:        "nafis",
+		Name:      "Nafis",
+		Email:     sql.NullString{String: "nafis@test.com", Valid: true},
+		Balance:   1000,
+		Rating:    90,
+		CreatedAt: createdAt,
+		Married:   true,
+	})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	_, err := customerRepository.CloneCustomer(ctx, "nafis", "nafis2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := customerRepository.FindById(ctx, "nafis2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Name != "Nafis" || clone.Email.String != "nafis@test.com" || clone.Balance != 1000 || clone.Rating != 90 || !clone.Married {
+		t.Fatalf("expected fields to be copied, got %+v", clone)
+	}
+	if clone.CreatedAt.Equal(createdAt) || time.Since(clone.CreatedAt) > time.Minute {
+		t.Fatalf("expected created_at to be reset to now, got %s", clone.CreatedAt)
+	}
+}
+
+func TestCustomerCloneMissingSource(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+
+	_, err := NewCustomerRepository(db).CloneCustomer(context.Background(), "budi", "budi2")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCustomerCloneCollidingTarget(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+
+	_, err := NewCustomerRepository(db).CloneCustomer(context.Background(), "nafis", "arya")
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+}
+
+func TestCustomerFindByIdsChunked(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	var customers []entity.Customer
+	for i := 0; i < 1000; i++ {
+		customers = append(customers, entity.Customer{Id: "chunk" + strconv.Itoa(i), Name: "Chunk"})
+	}
+	if err := customerRepository.InsertBatch(ctx, customers); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, 2500)
+	for i := range ids {
+		ids[i] = "chunk" + strconv.Itoa(i)
+	}
+	found, err := customerRepository.FindByIdsChunked(ctx, ids, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1000 {
+		t.Fatalf("expected 1000 customers, got %d", len(found))
+	}
+
+	seen := map[string]bool{}
+	for _, customer := range found {
+		if seen[customer.Id] {
+			t.Fatalf("customer %s returned twice", customer.Id)
+		}
+		seen[customer.Id] = true
+	}
+}
+
+func TestCustomerInsertDefaultStatus(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	inserted, err := customerRepository.Insert(ctx, entity.Customer{Id: "nafis", Name: "Nafis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted.Status != entity.StatusActive {
+		t.Fatalf("expected returned status active, got %q", inserted.Status)
+	}
+
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Status != entity.StatusActive {
+		t.Fatalf("expected stored status active, got %q", customer.Status)
+	}
+
+	_, err = customerRepository.Insert(ctx, entity.Customer{Id: "arya", Name: "Arya", Status: "deleted"})
+	if err == nil {
+		t.Fatal("expected unknown status to be rejected")
+	}
+}
+
+func TestCustomerExportNDJSON(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis", Email: sql.NullString{String: "nafis@test.com", Valid: true}},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+
+	var buffer bytes.Buffer
+	err := NewCustomerRepository(db).ExportNDJSON(context.Background(), &buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), buffer.String())
+	}
+	for _, line := range lines {
+		customer := entity.Customer{}
+		if err := json.Unmarshal([]byte(line), &customer); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		if customer.Id == "" || customer.Name == "" {
+			t.Fatalf("expected a customer, got %q", line)
+		}
+	}
+}
+
+func TestCustomerCountWhereMatchesFind(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "nadia", Name: "Nadia", Email: sql.NullString{String: "nadia@test.com", Valid: true}},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	isNull, notNull := true, false
+	filters := []CustomerFilter{
+		{},
+		{NameLike: "Na"},
+		{EmailIsNull: &isNull},
+		{EmailIsNull: &notNull},
+		{NameLike: "Na", EmailIsNull: &isNull},
+		{NameLike: "Budi"},
+	}
+	for _, filter := range filters {
+		customers, err := customerRepository.Find(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total, err := customerRepository.CountWhere(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != len(customers) {
+			t.Fatalf("filter %+v: CountWhere returned %d but Find returned %d rows", filter, total, len(customers))
+		}
+	}
+
+	total, err := customerRepository.CountWhere(ctx, CustomerFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 {
+		t.Fatalf("expected empty filter to count all 3 customers, got %d", total)
+	}
+}
+
+func TestCustomerInsertWithFixedClock(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	now := time.Date(2024, time.August, 17, 10, 0, 0, 0, time.UTC)
+
+	config := belajar_golang_database.DefaultConfig()
+	config.Clock = fixedClock{now: now}
+	customerRepository := NewCustomerRepositoryWithConfig(db, config)
+	ctx := context.Background()
+
+	inserted, err := customerRepository.Insert(ctx, entity.Customer{Id: "nafis", Name: "Nafis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inserted.CreatedAt.Equal(now) {
+		t.Fatalf("expected returned CreatedAt %s, got %s", now, inserted.CreatedAt)
+	}
+
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !customer.CreatedAt.Equal(now) {
+		t.Fatalf("expected stored CreatedAt %s, got %s", now, customer.CreatedAt)
+	}
+}
+
+func TestCustomerMergeByEmail(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	email := sql.NullString{String: "nafis@test.com", Valid: true}
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis-baru", Name: "Nafis", Email: email, Balance: 300, CreatedAt: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		entity.Customer{Id: "nafis-lama", Name: "Nafis", Email: email, Balance: 700, CreatedAt: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		entity.Customer{Id: "arya", Name: "Arya", Balance: 50},
+	)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	survivor, err := customerRepository.MergeByEmail(ctx, "nafis@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if survivor != "nafis-lama" {
+		t.Fatalf("expected the earliest customer to survive, got %s", survivor)
+	}
+
+	customer, err := customerRepository.FindById(ctx, "nafis-lama")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Balance != 1000 {
+		t.Fatalf("expected combined balance 1000, got %d", customer.Balance)
+	}
+	if exists, _ := customerRepository.Exists(ctx, "nafis-baru"); exists {
+		t.Fatal("expected the duplicate customer to be deleted")
+	}
+	if exists, _ := customerRepository.Exists(ctx, "arya"); !exists {
+		t.Fatal("expected other customers to be untouched")
+	}
+}
+
+func TestCustomerMergeByEmailSingleAndMissing(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis", Email: sql.NullString{String: "nafis@test.com", Valid: true}, Balance: 10})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	survivor, err := customerRepository.MergeByEmail(ctx, "nafis@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if survivor != "nafis" {
+		t.Fatalf("expected nafis, got %s", survivor)
+	}
+
+	_, err = customerRepository.MergeByEmail(ctx, "budi@test.com")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCustomerFindMissingEmail(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya", Email: sql.NullString{String: "", Valid: true}},
+		entity.Customer{Id: "budi", Name: "Budi", Email: sql.NullString{String: "budi@test.com", Valid: true}},
+	)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	customers, err := customerRepository.FindMissingEmail(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 2 || ids[0] != "arya" || ids[1] != "nafis" {
+		t.Fatalf("expected [arya nafis], got %v", ids)
+	}
+
+	customers, err = customerRepository.FindMissingEmail(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := customerIds(customers); len(ids) != 1 || ids[0] != "nafis" {
+		t.Fatalf("expected second page [nafis], got %v", ids)
+	}
+}
+
+func TestCustomerAddBalanceWhereRatingAbove(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis", Rating: 4.5, Balance: 10},
+		entity.Customer{Id: "arya", Name: "Arya", Rating: 4.0, Balance: 10},
+		entity.Customer{Id: "budi", Name: "Budi", Rating: 4.9, Balance: 20},
+		entity.Customer{Id: "joko", Name: "Joko", Rating: 2.0, Balance: 30},
+	)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	affected, err := customerRepository.AddBalanceWhereRatingAbove(ctx, 4, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 2 {
+		t.Fatalf("expected 2 affected rows, got %d", affected)
+	}
+
+	expected := map[string]int32{"nafis": 110, "arya": 10, "budi": 120, "joko": 30}
+	customers, err := customerRepository.FindAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, customer := range customers {
+		if customer.Balance != expected[customer.Id] {
+			t.Fatalf("expected %s balance %d, got %d", customer.Id, expected[customer.Id], customer.Balance)
+		}
+	}
+}
+
+func TestCustomerFindByIdForUpdateBlocksWriters(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if _, err := customerRepository.FindByIdForUpdate(ctx, tx, "nafis"); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := customerRepository.Update(ctx, entity.Customer{Id: "nafis", Name: "Nafis Arya"})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected the update to wait for the lock, it finished with %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the update to continue after commit")
+	}
+}
+
+func TestCustomerRecentCustomers(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		config := belajar_golang_database.DefaultConfig()
+		config.Clock = fixedClock{now: start.Add(time.Duration(i) * time.Hour)}
+		_, err := NewCustomerRepositoryWithConfig(db, config).Insert(ctx, entity.Customer{Id: id, Name: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// created_at sama dengan "e", urutannya ditentukan id
+	config := belajar_golang_database.DefaultConfig()
+	config.Clock = fixedClock{now: start.Add(4 * time.Hour)}
+	if _, err := NewCustomerRepositoryWithConfig(db, config).Insert(ctx, entity.Customer{Id: "f", Name: "f"}); err != nil {
+		t.Fatal(err)
+	}
+
+	customers, err := NewCustomerRepository(db).RecentCustomers(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 3 || ids[0] != "f" || ids[1] != "e" || ids[2] != "d" {
+		t.Fatalf("expected [f e d], got %v", ids)
+	}
+
+	indexed, err := QueryScalar[int](ctx, db, "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'customer' AND index_name = 'idx_customer_created_at'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indexed > 0 {
+		fullScan, err := belajar_golang_database.WarnIfFullScan(ctx, db, recentCustomersScript("customer"), 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fullScan {
+			t.Fatal("expected RecentCustomers to use idx_customer_created_at")
+		}
+	}
+}
+
+func TestCustomerAnonymize(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{
+		Id:        "nafis",
+		Name:      "Nafis",
+		Email:     sql.NullString{String: "nafis@test.com", Valid: true},
+		Balance:   250000,
+		BirthDate: sql.NullTime{Time: time.Date(1999, 9, 9, 0, 0, 0, 0, time.UTC), Valid: true},
+	})
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	if err := customerRepository.Anonymize(ctx, "nafis"); err != nil {
+		t.Fatal(err)
+	}
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != "REDACTED" || customer.Email.Valid || customer.BirthDate.Valid {
+		t.Fatalf("expected identifying fields to be scrubbed, got %+v", customer)
+	}
+	if customer.Balance != 250000 {
+		t.Fatalf("expected balance to be kept, got %d", customer.Balance)
+	}
+
+	// menganonimkan ulang tidak boleh dianggap id tidak ada
+	if err := customerRepository.Anonymize(ctx, "nafis"); err != nil {
+		t.Fatal(err)
+	}
+	if err := customerRepository.Anonymize(ctx, "budi"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCustomerForEachPage(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	var customers []entity.Customer
+	for i := 0; i < 50; i++ {
+		customers = append(customers, entity.Customer{Id: "customer" + strconv.Itoa(i), Name: "Customer"})
+	}
+	if err := NewCustomerRepository(db).InsertBatch(context.Background(), customers); err != nil {
+		t.Fatal(err)
+	}
+
+	visited := map[string]int{}
+	pages := 0
+	err := NewCustomerRepository(db).ForEachPage(context.Background(), 7, func(page []entity.Customer) error {
+		pages++
+		if len(page) > 7 {
+			t.Fatalf("expected at most 7 rows per page, got %d", len(page))
+		}
+		for _, customer := range page {
+			visited[customer.Id]++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages != 8 || len(visited) != 50 {
+		t.Fatalf("expected 50 rows over 8 pages, got %d rows over %d pages", len(visited), pages)
+	}
+	for id, count := range visited {
+		if count != 1 {
+			t.Fatalf("expected %s to be visited once, got %d", id, count)
+		}
+	}
+
+	stop := errors.New("stop")
+	pages = 0
+	err = NewCustomerRepository(db).ForEachPage(context.Background(), 7, func(page []entity.Customer) error {
+		pages++
+		return stop
+	})
+	if !errors.Is(err, stop) || pages != 1 {
+		t.Fatalf("expected to stop after the failing page, got %v after %d pages", err, pages)
+	}
+}
+
+func TestCustomerInsertIgnore(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	inserted, err := customerRepository.InsertIgnore(ctx, entity.Customer{Id: "nafis", Name: "Nafis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inserted {
+		t.Fatal("expected a new id to be inserted")
+	}
+
+	inserted, err = customerRepository.InsertIgnore(ctx, entity.Customer{Id: "nafis", Name: "Nafis Baru"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted {
+		t.Fatal("expected a duplicate id to be skipped")
+	}
+
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != "Nafis" {
+		t.Fatalf("expected the existing row to be kept, got %s", customer.Name)
+	}
+}
+
+func TestCustomerBalanceHistory(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"}, entity.Customer{Id: "arya", Name: "Arya"})
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// sengaja tidak diinsert berurutan supaya urutan kronologisnya diuji
+	events := []struct {
+		amount int32
+		at     time.Time
+	}{
+		{-30000, start.Add(2 * time.Hour)},
+		{100000, start},
+		{5000, start.Add(3 * time.Hour)},
+		{25000, start.Add(time.Hour)},
+	}
+	for _, event := range events {
+		script := "INSERT INTO balance_audit(customer_id, amount, description, created_at) VALUES (?,?,?,?)"
+		_, err := db.ExecContext(ctx, script, "nafis", event.amount, "test", event.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	customerRepository := NewCustomerRepository(db)
+	history, err := customerRepository.BalanceHistory(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int64{100000, 125000, 95000, 100000}
+	if len(history) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(history))
+	}
+	for i, event := range history {
+		if event.Balance != expected[i] {
+			t.Fatalf("event %d: expected running balance %d, got %d", i, expected[i], event.Balance)
+		}
+		if !event.CreatedAt.Equal(start.Add(time.Duration(i) * time.Hour)) {
+			t.Fatalf("event %d: expected chronological order, got %s", i, event.CreatedAt)
+		}
+	}
+
+	history, err = customerRepository.BalanceHistory(ctx, "arya")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history == nil || len(history) != 0 {
+		t.Fatalf("expected an empty history, got %v", history)
+	}
+}
+
+func TestCustomerInsertFromChannel(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+
+	in := make(chan entity.Customer)
+	go func() {
+		defer close(in)
+		for i := 0; i < 250; i++ {
+			in <- entity.Customer{Id: "customer" + strconv.Itoa(i), Name: "Customer"}
+		}
+	}()
+
+	inserted, err := customerRepository.InsertFromChannel(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, err := customerRepository.Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted != 250 || total != 250 {
+		t.Fatalf("expected 250 customers, got %d inserted and %d stored", inserted, total)
+	}
+}
+
+func TestCustomerInsertFromChannelCancelled(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan entity.Customer)
+	go func() {
+		// channel tidak pernah ditutup, hanya cancel yang bisa menghentikan InsertFromChannel
+		for i := 0; i < 150; i++ {
+			in <- entity.Customer{Id: "customer" + strconv.Itoa(i), Name: "Customer"}
+		}
+		cancel()
+	}()
+
+	inserted, err := customerRepository.InsertFromChannel(ctx, in)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	total, err := customerRepository.Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted != 100 || total != 100 {
+		t.Fatalf("expected only the flushed batch of 100, got %d inserted and %d stored", inserted, total)
+	}
+}
+
+func TestCustomerTablePrefix(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS app_customer")
+		db.Exec("DROP TABLE IF EXISTS app_customer_archive")
+		db.Exec("DROP TABLE IF EXISTS app_user")
+	})
+
+	config := belajar_golang_database.DefaultConfig()
+	config.TablePrefix = "app_"
+	if err := belajar_golang_database.MigrateWithConfig(ctx, db, config); err != nil {
+		t.Fatal(err)
+	}
+
+	customerRepository := NewCustomerRepositoryWithConfig(db, config)
+	if _, err := customerRepository.Insert(ctx, entity.Customer{Id: "nafis", Name: "Nafis"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := customerRepository.Update(ctx, entity.Customer{Id: "nafis", Name: "Nafis Baru"}); err != nil {
+		t.Fatal(err)
+	}
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != "Nafis Baru" {
+		t.Fatalf("expected updated name, got %s", customer.Name)
+	}
+
+	prefixed, err := QueryScalar[int](ctx, db, "SELECT COUNT(*) FROM app_customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bare, err := QueryScalar[int](ctx, db, "SELECT COUNT(*) FROM customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefixed != 1 || bare != 0 {
+		t.Fatalf("expected the row only in app_customer, got app_customer=%d customer=%d", prefixed, bare)
+	}
+
+	if _, err := customerRepository.Delete(ctx, "nafis"); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := customerRepository.Exists(ctx, "nafis"); exists {
+		t.Fatal("expected customer to be deleted from app_customer")
+	}
+
+	_, err = NewUserRepositoryWithConfig(db, config).FindAll(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("expected app_user to exist: %v", err)
+	}
+}
+
+func TestCustomerLookupsUseIndex(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	var customers []entity.Customer
+	for i := 0; i < 20; i++ {
+		id := "customer" + strconv.Itoa(i)
+		customers = append(customers, entity.Customer{Id: id, Name: "Customer", Email: sql.NullString{String: id + "@test.com", Valid: true}})
+	}
+	customerRepository := NewCustomerRepository(db).(*customerRepositoryImpl)
+	if err := customerRepository.InsertBatch(context.Background(), customers); err != nil {
+		t.Fatal(err)
+	}
+
+	// statement yang benar-benar dijalankan FindById dan MergeByEmail
+	belajar_golang_database.AssertUsesIndex(t, db, findByIdScript(customerRepository.Table), "customer5")
+	belajar_golang_database.AssertUsesIndex(t, db, mergeByEmailScript(customerRepository.Table), "customer5@test.com")
+}
+
+func TestCustomerCountDistinct(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis1", Name: "Nafis"},
+		entity.Customer{Id: "nafis2", Name: "Nafis"},
+		entity.Customer{Id: "arya1", Name: "Arya"},
+		entity.Customer{Id: "arya2", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+	customerRepository := NewCustomerRepository(db)
+
+	distinct, err := customerRepository.CountDistinct(context.Background(), "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distinct != 3 {
+		t.Fatalf("expected 3 distinct names out of 5 rows, got %d", distinct)
+	}
+
+	_, err = customerRepository.CountDistinct(context.Background(), "name) FROM user; --")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCustomerInsertBatchCancelledBetweenChunks(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chunks := 0
+	original := afterBatchChunk
+	afterBatchChunk = func() {
+		chunks++
+		cancel()
+	}
+	t.Cleanup(func() { afterBatchChunk = original })
+
+	var customers []entity.Customer
+	for i := 0; i < customerBatchSize*2; i++ {
+		customers = append(customers, entity.Customer{Id: "customer" + strconv.Itoa(i), Name: "Customer"})
+	}
+
+	err := NewCustomerRepository(db).InsertBatch(ctx, customers)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if chunks != 1 {
+		t.Fatalf("expected to stop after the first chunk, got %d chunks", chunks)
+	}
+	total, err := NewCustomerRepository(db).Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("expected the first chunk to be rolled back, got %d rows", total)
+	}
+}
+
+func TestCustomerEmptyStringAsNull(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+	empty := sql.NullString{String: "", Valid: true}
+
+	config := belajar_golang_database.DefaultConfig()
+	config.EmptyStringAsNull = true
+	customerRepository := NewCustomerRepositoryWithConfig(db, config)
+	if _, err := customerRepository.Insert(ctx, entity.Customer{Id: "nafis", Name: "Nafis", Email: empty}); err != nil {
+		t.Fatal(err)
+	}
+	customer, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Email.Valid {
+		t.Fatalf("expected empty email to be stored as NULL, got %+v", customer.Email)
+	}
+
+	// tanpa opsi, string kosong tetap disimpan apa adanya
+	plainRepository := NewCustomerRepository(db)
+	if _, err := plainRepository.Insert(ctx, entity.Customer{Id: "arya", Name: "Arya", Email: empty}); err != nil {
+		t.Fatal(err)
+	}
+	customer, err = plainRepository.FindById(ctx, "arya")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !customer.Email.Valid || customer.Email.String != "" {
+		t.Fatalf("expected empty email to be kept, got %+v", customer.Email)
+	}
+
+	if _, err := customerRepository.Update(ctx, customer); err != nil {
+		t.Fatal(err)
+	}
+	customer, err = plainRepository.FindById(ctx, "arya")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Email.Valid {
+		t.Fatalf("expected Update to store the empty email as NULL, got %+v", customer.Email)
+	}
+}
+
+func TestCustomerEmptyStringAsNullOnEveryInsertPath(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+	empty := sql.NullString{String: "", Valid: true}
+	seedCustomers(t, db, entity.Customer{Id: "sumber", Name: "Sumber", Email: empty})
+
+	config := belajar_golang_database.DefaultConfig()
+	config.EmptyStringAsNull = true
+	customerRepository := NewCustomerRepositoryWithConfig(db, config)
+	if _, err := customerRepository.Onboard(ctx, entity.Customer{Id: "onboard", Name: "Onboard", Email: empty}, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := customerRepository.InsertIdempotent(ctx, "key-1", entity.Customer{Id: "idempotent", Name: "Idempotent", Email: empty}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := customerRepository.FindOrCreate(ctx, entity.Customer{Id: "created", Name: "Created", Email: empty}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := customerRepository.CloneCustomer(ctx, "sumber", "clone"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"onboard", "idempotent", "created", "clone"} {
+		customer, err := customerRepository.FindById(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if customer.Email.Valid {
+			t.Fatalf("expected %s to store the empty email as NULL, got %+v", id, customer.Email)
+		}
+	}
+}
+
+func TestCustomerFindByRatingBetween(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "a", Name: "A", Rating: 1.0},
+		entity.Customer{Id: "b", Name: "B", Rating: 2.5},
+		entity.Customer{Id: "c", Name: "C", Rating: 3.0},
+		entity.Customer{Id: "d", Name: "D", Rating: 4.0},
+		entity.Customer{Id: "e", Name: "E", Rating: 4.5},
+	)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	customers, err := customerRepository.FindByRatingBetween(ctx, 2.5, 4.0, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 3 || ids[0] != "b" || ids[1] != "c" || ids[2] != "d" {
+		t.Fatalf("expected [b c d] including both boundaries, got %v", ids)
+	}
+
+	customers, err = customerRepository.FindByRatingBetween(ctx, 2.5, 4.0, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = customerIds(customers)
+	if len(ids) != 1 || ids[0] != "d" {
+		t.Fatalf("expected the second page to be [d], got %v", ids)
+	}
+
+	_, err = customerRepository.FindByRatingBetween(ctx, 4.0, 2.5, 10, 0)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCustomerBalancePercentile(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	var customers []entity.Customer
+	// saldo 100, 200, ..., 1000, diinsert terbalik
+	for i := 10; i >= 1; i-- {
+		customers = append(customers, entity.Customer{Id: "customer" + strconv.Itoa(i), Name: "Customer", Balance: int32(i * 100)})
+	}
+	seedCustomers(t, db, customers...)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	median, err := customerRepository.BalancePercentile(ctx, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if median != 500 {
+		t.Fatalf("expected median 500, got %d", median)
+	}
+
+	p90, err := customerRepository.BalancePercentile(ctx, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p90 != 900 {
+		t.Fatalf("expected p90 900, got %d", p90)
+	}
+
+	_, err = customerRepository.BalancePercentile(ctx, 101)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestCustomerFindByIdsMap(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+
+	byId, err := NewCustomerRepository(db).FindByIdsMap(context.Background(), []string{"nafis", "arya", "budi", "nadia"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byId) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(byId))
+	}
+	if byId["arya"].Name != "Arya" {
+		t.Fatalf("expected arya to map to Arya, got %+v", byId["arya"])
+	}
+	if _, ok := byId["nadia"]; ok {
+		t.Fatal("expected the missing id to be absent")
+	}
+}
+
+func TestCustomerSampleCustomers(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	var customers []entity.Customer
+	for i := 0; i < 30; i++ {
+		customers = append(customers, entity.Customer{Id: "customer" + strconv.Itoa(i), Name: "Customer"})
+	}
+	seedCustomers(t, db, customers...)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	sample := func(seed int64) string {
+		t.Helper()
+		customers, err := customerRepository.SampleCustomers(ctx, 5, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(customers) != 5 {
+			t.Fatalf("expected 5 customers, got %d", len(customers))
+		}
+		return strings.Join(customerIds(customers), ",")
+	}
+
+	first := sample(42)
+	if second := sample(42); first != second {
+		t.Fatalf("expected the same seed to give the same sample, got %s and %s", first, second)
+	}
+
+	differs := false
+	for seed := int64(1); seed <= 5 && !differs; seed++ {
+		differs = sample(seed) != first
+	}
+	if !differs {
+		t.Fatal("expected other seeds to give a different sample")
+	}
+}
+
+func TestCustomerScanNullIntoNonNullableField(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	_, err := db.Exec("INSERT INTO customer(id, name, balance) VALUES ('nafis', 'Nafis', NULL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewCustomerRepository(db).FindById(context.Background(), "nafis")
+	if !errors.Is(err, ErrUnexpectedNull) || !strings.Contains(err.Error(), "column balance") {
+		t.Fatalf("expected ErrUnexpectedNull naming balance, got %v", err)
+	}
+}
+
+func TestCustomerScanNullMarriedAndStatus(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	_, err := db.Exec("INSERT INTO customer(id, name, married) VALUES ('nafis', 'Nafis', NULL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customerRepository := NewCustomerRepository(db)
+	_, err = customerRepository.FindById(context.Background(), "nafis")
+	if !errors.Is(err, ErrUnexpectedNull) || !strings.Contains(err.Error(), "column married") {
+		t.Fatalf("expected ErrUnexpectedNull naming married, got %v", err)
+	}
+
+	// status NOT NULL di skema, jadi NULL-nya dibuat di SELECT
+	script := "SELECT id, name, email, balance, rating, created_at, birth_date, false, NULL FROM customer"
+	_, err = Collect(context.Background(), db, script, customerRepository.(*customerRepositoryImpl).scanCustomer)
+	if !errors.Is(err, ErrUnexpectedNull) || !strings.Contains(err.Error(), "column status") {
+		t.Fatalf("expected ErrUnexpectedNull naming status, got %v", err)
+	}
+}
+
+func TestCustomerUpdateBatch(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	var customers []entity.Customer
+	for i := 0; i < 200; i++ {
+		customers = append(customers, entity.Customer{Id: "customer" + strconv.Itoa(i), Name: "Customer"})
+	}
+	if err := customerRepository.InsertBatch(ctx, customers); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range customers {
+		customers[i].Name = "Updated " + strconv.Itoa(i)
+		customers[i].Balance = int32(i)
+	}
+	// id yang tidak ada diabaikan, tidak ikut diinsert
+	updates := append(customers, entity.Customer{Id: "ghost", Name: "Ghost"})
+	if err := customerRepository.UpdateBatch(ctx, updates); err != nil {
+		t.Fatal(err)
+	}
+
+	byId, err := customerRepository.FindByIdsMap(ctx, append(customerIds(customers), "ghost"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byId) != 200 {
+		t.Fatalf("expected 200 customers and no ghost, got %d", len(byId))
+	}
+	for i, customer := range customers {
+		stored := byId[customer.Id]
+		if stored.Name != "Updated "+strconv.Itoa(i) || stored.Balance != int32(i) {
+			t.Fatalf("expected %s to be updated, got %+v", customer.Id, stored)
+		}
+	}
+}
+
+func TestCustomerPurgeDeletedBefore(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "lama", Name: "Lama"},
+		entity.Customer{Id: "kemarin", Name: "Kemarin"},
+		entity.Customer{Id: "baru", Name: "Baru"},
+		entity.Customer{Id: "aktif", Name: "Aktif"},
+	)
+	ctx := context.Background()
+
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	deleted := map[string]time.Time{
+		"lama":    cutoff.AddDate(0, -3, 0),
+		"kemarin": cutoff.Add(-time.Hour),
+		"baru":    cutoff.Add(time.Hour),
+	}
+	for id, at := range deleted {
+		if _, err := db.ExecContext(ctx, "UPDATE customer SET deleted_at = ? WHERE id = ?", at, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	customerRepository := NewCustomerRepository(db)
+	purged, err := customerRepository.PurgeDeletedBefore(ctx, cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 2 {
+		t.Fatalf("expected 2 purged rows, got %d", purged)
+	}
+	for id, expected := range map[string]bool{"lama": false, "kemarin": false, "baru": true, "aktif": true} {
+		exists, err := customerRepository.Exists(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != expected {
+			t.Fatalf("expected %s exists=%v, got %v", id, expected, exists)
+		}
+	}
+}
+
+func TestCustomerArchive(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "arsip", Name: "Arsip", Balance: 500},
+		entity.Customer{Id: "aktif", Name: "Aktif"},
+	)
+	ctx := context.Background()
+
+	customerRepository := NewCustomerRepository(db)
+	if err := customerRepository.Archive(ctx, "arsip"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := customerRepository.FindById(ctx, "arsip"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected arsip gone from the live table, got %v", err)
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM customer_archive WHERE id = ?", "arsip").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected arsip in the archive, got %d rows", count)
+	}
+
+	if err := customerRepository.Archive(ctx, "tidak-ada"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCustomerArchiveAfterMigratingOldSchema(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+	t.Cleanup(func() {
+		for _, table := range []string{"old_customer", "old_customer_archive", "old_user"} {
+			db.Exec("DROP TABLE IF EXISTS " + table)
+		}
+	})
+
+	// skema customer sebelum kolom status dan deleted_at
+	_, err := db.ExecContext(ctx, `CREATE TABLE old_customer (
+		id VARCHAR(100) NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		email VARCHAR(100),
+		balance INT DEFAULT 0,
+		rating DOUBLE DEFAULT 0.0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		birth_date DATE,
+		married BOOLEAN DEFAULT false,
+		PRIMARY KEY (id)
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO old_customer(id, name, balance) VALUES ('nafis', 'Nafis', 500)"); err != nil {
+		t.Fatal(err)
+	}
+
+	config := belajar_golang_database.DefaultConfig()
+	config.TablePrefix = "old_"
+	if err := belajar_golang_database.MigrateWithConfig(ctx, db, config); err != nil {
+		t.Fatal(err)
+	}
+
+	customerRepository := NewCustomerRepositoryWithConfig(db, config)
+	if err := customerRepository.Archive(ctx, "nafis"); err != nil {
+		t.Fatal(err)
+	}
+	customer, fromArchive, err := customerRepository.FindByIdAnyArchive(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromArchive || customer.Balance != 500 || customer.Status != entity.StatusActive {
+		t.Fatalf("expected nafis in the migrated archive, got %+v, fromArchive=%v", customer, fromArchive)
+	}
+}
+
+func TestCustomerFindByIdAnyArchive(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "arsip", Name: "Arsip", Balance: 500},
+		entity.Customer{Id: "aktif", Name: "Aktif"},
+	)
+	ctx := context.Background()
+
+	customerRepository := NewCustomerRepository(db)
+	if err := customerRepository.Archive(ctx, "arsip"); err != nil {
+		t.Fatal(err)
+	}
+
+	customer, fromArchive, err := customerRepository.FindByIdAnyArchive(ctx, "arsip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromArchive || customer.Name != "Arsip" || customer.Balance != 500 {
+		t.Fatalf("expected arsip from the archive, got %+v, fromArchive=%v", customer, fromArchive)
+	}
+
+	customer, fromArchive, err = customerRepository.FindByIdAnyArchive(ctx, "aktif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromArchive || customer.Id != "aktif" {
+		t.Fatalf("expected aktif from the live table, got %+v, fromArchive=%v", customer, fromArchive)
+	}
+
+	if _, _, err := customerRepository.FindByIdAnyArchive(ctx, "tidak-ada"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func customerUpdatedAt(t *testing.T, db *sql.DB, id string) sql.NullTime {
+	t.Helper()
+	var updatedAt sql.NullTime
+	err := db.QueryRowContext(context.Background(), "SELECT updated_at FROM customer WHERE id = ?", id).Scan(&updatedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return updatedAt
+}
+
+func TestCustomerTouch(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+	ctx := context.Background()
+
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	config := belajar_golang_database.DefaultConfig()
+	config.Clock = fixedClock{now: now}
+	customerRepository := NewCustomerRepositoryWithConfig(db, config)
+
+	touched, err := customerRepository.Touch(ctx, []string{"nafis", "arya", "tidak-ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if touched != 2 {
+		t.Fatalf("expected 2 touched rows, got %d", touched)
+	}
+	for _, id := range []string{"nafis", "arya"} {
+		if updatedAt := customerUpdatedAt(t, db, id); !updatedAt.Valid || !updatedAt.Time.Equal(now) {
+			t.Fatalf("expected %s updated_at %s, got %+v", id, now, updatedAt)
+		}
+	}
+	if updatedAt := customerUpdatedAt(t, db, "budi"); updatedAt.Valid {
+		t.Fatalf("expected budi untouched, got %+v", updatedAt)
+	}
+}
+
+func TestCustomerUpdateBumpsUpdatedAt(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	ctx := context.Background()
+
+	start := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	for i, name := range []string{"Nafis", "Nafis Arya"} {
+		now := start.Add(time.Duration(i) * time.Minute)
+		config := belajar_golang_database.DefaultConfig()
+		config.Clock = fixedClock{now: now}
+		if _, err := NewCustomerRepositoryWithConfig(db, config).Update(ctx, entity.Customer{Id: "nafis", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+		if updatedAt := customerUpdatedAt(t, db, "nafis"); !updatedAt.Valid || !updatedAt.Time.Equal(now) {
+			t.Fatalf("update %d: expected updated_at %s, got %+v", i, now, updatedAt)
+		}
+	}
+}
+
+func TestCustomerApplyDiff(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis", Email: sql.NullString{String: "nafis@test.com", Valid: true}, Balance: 100, Rating: 4.5})
+	ctx := context.Background()
+
+	customerRepository := NewCustomerRepository(db)
+	before, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := before
+	changed.Name = "Nafis Arya"
+	changed.Balance = 250
+
+	changes := Diff(before, changed)
+	if len(changes) != 2 || changes["name"] != "Nafis Arya" || changes["balance"] != int32(250) {
+		t.Fatalf("expected exactly name and balance, got %v", changes)
+	}
+	if err := customerRepository.ApplyDiff(ctx, "nafis", changes); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := customerRepository.FindById(ctx, "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored := Diff(before, after); len(stored) != 2 || stored["name"] != "Nafis Arya" || stored["balance"] != int32(250) {
+		t.Fatalf("expected only name and balance to change in storage, got %v", stored)
+	}
+
+	if err := customerRepository.ApplyDiff(ctx, "nafis", map[string]any{}); err != nil {
+		t.Fatalf("expected an empty diff to be a no-op, got %v", err)
+	}
+	if err := customerRepository.ApplyDiff(ctx, "nafis", map[string]any{"name = 'x', balance": 1}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument for an unknown column, got %v", err)
+	}
+	if err := customerRepository.ApplyDiff(ctx, "tidak-ada", changes); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DBTX is satisfied by both *sql.DB and *sql.Tx
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// QueryScalar scans the single value returned by query into T
+func QueryScalar[T any](ctx context.Context, db DBTX, query string, args ...any) (T, error) {
+	var value T
+	err := db.QueryRowContext(ctx, query, args...).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return value, ErrNotFound
+	}
+	return value, err
+}
+
+// Query calls scan for every row and always closes the rows, even when scan fails
+func Query(ctx context.Context, db DBTX, query string, scan func(*sql.Rows) error, args ...any) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := scan(rows)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Collect maps every row with mapper and returns the results, the rows are always closed
+func Collect[T any](ctx context.Context, db DBTX, query string, mapper func(*sql.Rows) (T, error), args ...any) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []T
+	for rows.Next() {
+		result, err := mapper(rows)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", len(results)+1, err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// ExecResult is sql.Result read eagerly. A value the driver can not report is
+// left at -1 and its Has flag is false.
+type ExecResult struct {
+	RowsAffected    int64
+	LastInsertId    int64
+	HasRowsAffected bool
+	HasLastInsertId bool
+}
+
+// Exec runs query and reads both values of its sql.Result
+func Exec(ctx context.Context, db DBTX, query string, args ...any) (ExecResult, error) {
+	execResult := ExecResult{RowsAffected: -1, LastInsertId: -1}
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return execResult, err
+	}
+
+	if affected, err := result.RowsAffected(); err == nil {
+		execResult.RowsAffected, execResult.HasRowsAffected = affected, true
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		execResult.LastInsertId, execResult.HasLastInsertId = id, true
+	}
+	return execResult, nil
+}
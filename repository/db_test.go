@@ -0,0 +1,193 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryScalar(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+	ctx := context.Background()
+
+	total, err := QueryScalar[int](ctx, db, "SELECT COUNT(*) FROM customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("expected count 2, got %d", total)
+	}
+
+	name, err := QueryScalar[string](ctx, db, "SELECT name FROM customer WHERE id = ?", "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Nafis" {
+		t.Fatalf("expected name Nafis, got %s", name)
+	}
+
+	_, err = QueryScalar[string](ctx, db, "SELECT name FROM customer WHERE id = ?", "budi")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestQueryScanEveryRow(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+
+	var names []string
+	err := Query(context.Background(), db, "SELECT name FROM customer ORDER BY id", func(rows *sql.Rows) error {
+		var name string
+		err := rows.Scan(&name)
+		names = append(names, name)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 || names[0] != "Arya" || names[1] != "Budi" || names[2] != "Nafis" {
+		t.Fatalf("expected one callback per row, got %v", names)
+	}
+}
+
+func TestQueryClosesRowsOnScanError(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	db.SetMaxOpenConns(1)
+
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+
+	scanErr := errors.New("stop")
+	calls := 0
+	err := Query(context.Background(), db, "SELECT id FROM customer", func(rows *sql.Rows) error {
+		calls++
+		return scanErr
+	})
+	if !errors.Is(err, scanErr) {
+		t.Fatalf("expected scan error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected query to stop after the first row, got %d calls", calls)
+	}
+
+	// dengan pool berisi 1 koneksi, query berikutnya hanya bisa jalan jika rows sudah di-close
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := QueryScalar[int](ctx, db, "SELECT COUNT(*) FROM customer"); err != nil {
+		t.Fatalf("connection was not released: %v", err)
+	}
+}
+
+func TestCollectWithDifferentMappers(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+	ctx := context.Background()
+
+	names, err := Collect(ctx, db, "SELECT name FROM customer ORDER BY id", func(rows *sql.Rows) (string, error) {
+		var name string
+		err := rows.Scan(&name)
+		return name, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "Arya" || names[1] != "Nafis" {
+		t.Fatalf("expected [Arya Nafis], got %v", names)
+	}
+
+	repository := NewCustomerRepository(db).(*customerRepositoryImpl)
+	customers, err := Collect(ctx, db, "SELECT "+customerColumns+" FROM customer ORDER BY id", repository.scanCustomer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 2 || customers[0].Id != "arya" || customers[1].Name != "Nafis" {
+		t.Fatalf("expected arya and nafis, got %+v", customers)
+	}
+}
+
+func TestCollectMapperError(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+
+	mapperErr := errors.New("stop")
+	calls := 0
+	ids, err := Collect(context.Background(), db, "SELECT id FROM customer", func(rows *sql.Rows) (string, error) {
+		calls++
+		return "", mapperErr
+	})
+	if !errors.Is(err, mapperErr) || err == mapperErr {
+		t.Fatalf("expected a wrapped mapper error, got %v", err)
+	}
+	if ids != nil || calls != 1 {
+		t.Fatalf("expected collect to abort after the first row, got %v after %d calls", ids, calls)
+	}
+}
+
+func TestExecInsertAndUpdate(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis"},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+	ctx := context.Background()
+
+	inserted, err := Exec(ctx, db, "INSERT INTO outbox(topic, payload) VALUES (?, ?)", "customer.created", "{}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inserted.HasLastInsertId || inserted.LastInsertId <= 0 || inserted.RowsAffected != 1 {
+		t.Fatalf("expected an auto increment id and 1 affected row, got %+v", inserted)
+	}
+
+	updated, err := Exec(ctx, db, "UPDATE customer SET balance = 100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.HasRowsAffected || updated.RowsAffected != 2 {
+		t.Fatalf("expected 2 affected rows, got %+v", updated)
+	}
+}
+
+// rowsAffectedDB returns a result without LastInsertId, like drivers that do not support it
+type rowsAffectedDB struct {
+	DBTX
+}
+
+func (rowsAffectedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return driver.RowsAffected(3), nil
+}
+
+func TestExecWithoutLastInsertId(t *testing.T) {
+	result, err := Exec(context.Background(), rowsAffectedDB{}, "UPDATE customer SET balance = 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.HasLastInsertId || result.LastInsertId != -1 {
+		t.Fatalf("expected LastInsertId -1 when unsupported, got %+v", result)
+	}
+	if !result.HasRowsAffected || result.RowsAffected != 3 {
+		t.Fatalf("expected 3 affected rows, got %+v", result)
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"belajar-golang-database/entity"
+	"database/sql"
+	"time"
+)
+
+// Diff returns the columns whose value differs from oldCustomer to newCustomer,
+// mapped to the new value. id is never part of the diff, it identifies the row.
+func Diff(oldCustomer entity.Customer, newCustomer entity.Customer) map[string]any {
+	oldFields := customerFields(&oldCustomer)
+	changes := map[string]any{}
+	for column, field := range customerFields(&newCustomer) {
+		if column == "id" {
+			continue
+		}
+		oldValue, newValue := fieldValue(oldFields[column]), fieldValue(field)
+		if !sameValue(oldValue, newValue) {
+			changes[column] = newValue
+		}
+	}
+	return changes
+}
+
+func fieldValue(field any) any {
+	switch field := field.(type) {
+	case *string:
+		return *field
+	case *sql.NullString:
+		return *field
+	case *int32:
+		return *field
+	case *float64:
+		return *field
+	case *time.Time:
+		return *field
+	case *sql.NullTime:
+		return *field
+	case *entity.Bool:
+		return *field
+	case *entity.Status:
+		return *field
+	}
+	panic("repository: no fieldValue case for customer field type")
+}
+
+// sameValue compares two NULLs as equal whatever their zero value holds,
+// and timestamps by instant instead of by location
+func sameValue(a any, b any) bool {
+	switch a := a.(type) {
+	case sql.NullString:
+		b := b.(sql.NullString)
+		return a.Valid == b.Valid && (!a.Valid || a.String == b.String)
+	case sql.NullTime:
+		b := b.(sql.NullTime)
+		return a.Valid == b.Valid && (!a.Valid || a.Time.Equal(b.Time))
+	case time.Time:
+		return a.Equal(b.(time.Time))
+	}
+	return a == b
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"belajar-golang-database/entity"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestDiffComparesNullsAndTimes(t *testing.T) {
+	createdAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	oldCustomer := entity.Customer{Id: "nafis", Name: "Nafis", Email: sql.NullString{String: "sisa"}, CreatedAt: createdAt}
+	newCustomer := entity.Customer{Id: "lain", Name: "Nafis", CreatedAt: createdAt.In(time.FixedZone("WIB", 7*60*60))}
+	if changes := Diff(oldCustomer, newCustomer); len(changes) != 0 {
+		t.Fatalf("expected no changes between two NULL emails and the same instant, got %v", changes)
+	}
+
+	newCustomer.Email = sql.NullString{String: "", Valid: true}
+	changes := Diff(oldCustomer, newCustomer)
+	if len(changes) != 1 || changes["email"] != newCustomer.Email {
+		t.Fatalf("expected NULL to empty string to be a change, got %v", changes)
+	}
+}
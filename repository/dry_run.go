@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// DryRun makes Delete, DeleteByIds and TruncateTables log the SQL they would
+// run and return the number of rows that would be affected, without deleting
+var DryRun bool
+
+func dryRunCount(ctx context.Context, db DBTX, script string, countScript string, args ...any) (int64, error) {
+	log.Println("[dry-run]", script, args)
+	return QueryScalar[int64](ctx, db, countScript, args...)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrDuplicateKey    = errors.New("duplicate key")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrResultTooLarge  = errors.New("result has more rows than the configured MaxRows")
+	ErrReadOnly        = errors.New("write rejected by read-only connection")
+	ErrUnexpectedNull  = errors.New("NULL in a non-nullable field")
+)
+
+const mysqlErrDuplicateEntry = 1062
+
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"belajar-golang-database/entity"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// exporter writes customers in one format, Write is called once per row between Begin and End
+type exporter interface {
+	Begin() error
+	Write(customer entity.Customer) error
+	End() error
+}
+
+func newExporter(w io.Writer, format string) (exporter, error) {
+	switch format {
+	case "ndjson":
+		return &ndjsonExporter{encoder: json.NewEncoder(w)}, nil
+	case "json":
+		return &jsonExporter{w: w}, nil
+	case "csv":
+		return &csvExporter{writer: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown export format %q", ErrInvalidArgument, format)
+}
+
+type ndjsonExporter struct {
+	encoder *json.Encoder
+}
+
+func (exporter *ndjsonExporter) Begin() error {
+	return nil
+}
+
+func (exporter *ndjsonExporter) Write(customer entity.Customer) error {
+	return exporter.encoder.Encode(customer)
+}
+
+func (exporter *ndjsonExporter) End() error {
+	return nil
+}
+
+// jsonExporter writes a single array, element by element
+type jsonExporter struct {
+	w     io.Writer
+	count int
+}
+
+func (exporter *jsonExporter) Begin() error {
+	_, err := io.WriteString(exporter.w, "[")
+	return err
+}
+
+func (exporter *jsonExporter) Write(customer entity.Customer) error {
+	bytes, err := json.Marshal(customer)
+	if err != nil {
+		return err
+	}
+	if exporter.count > 0 {
+		if _, err := io.WriteString(exporter.w, ","); err != nil {
+			return err
+		}
+	}
+	exporter.count++
+	_, err = exporter.w.Write(bytes)
+	return err
+}
+
+func (exporter *jsonExporter) End() error {
+	_, err := io.WriteString(exporter.w, "]\n")
+	return err
+}
+
+type csvExporter struct {
+	writer *csv.Writer
+}
+
+func (exporter *csvExporter) Begin() error {
+	return exporter.writer.Write([]string{"id", "name", "email", "balance", "rating", "created_at", "birth_date", "married", "status"})
+}
+
+func (exporter *csvExporter) Write(customer entity.Customer) error {
+	birthDate := ""
+	if customer.BirthDate.Valid {
+		birthDate = customer.BirthDate.Time.Format(time.DateOnly)
+	}
+	err := exporter.writer.Write([]string{
+		customer.Id,
+		customer.Name,
+		customer.Email.String,
+		strconv.Itoa(int(customer.Balance)),
+		strconv.FormatFloat(customer.Rating, 'f', -1, 64),
+		customer.CreatedAt.Format(time.RFC3339),
+		birthDate,
+		strconv.FormatBool(bool(customer.Married)),
+		string(customer.Status),
+	})
+	if err != nil {
+		return err
+	}
+	// csv.Writer menahan data di buffer, di-flush per baris supaya sampai ke w
+	exporter.writer.Flush()
+	return exporter.writer.Error()
+}
+
+func (exporter *csvExporter) End() error {
+	exporter.writer.Flush()
+	return exporter.writer.Error()
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func seedExportCustomers(t *testing.T, db *sql.DB) {
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis", Email: sql.NullString{String: "nafis@test.com", Valid: true}},
+		entity.Customer{Id: "arya", Name: "Arya, Jr."},
+		entity.Customer{Id: "budi", Name: "Budi"},
+	)
+}
+
+func TestStreamExportCSV(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedExportCustomers(t, db)
+
+	var buffer bytes.Buffer
+	err := NewCustomerRepository(db).StreamExport(context.Background(), &buffer, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || records[0][0] != "id" {
+		t.Fatalf("expected a header and 3 rows, got %v", records)
+	}
+	if records[1][0] != "arya" || records[1][1] != "Arya, Jr." {
+		t.Fatalf("expected the comma in the name to be quoted, got %v", records[1])
+	}
+}
+
+func TestStreamExportJSON(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedExportCustomers(t, db)
+
+	var buffer bytes.Buffer
+	err := NewCustomerRepository(db).StreamExport(context.Background(), &buffer, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var customers []entity.Customer
+	if err := json.Unmarshal(buffer.Bytes(), &customers); err != nil {
+		t.Fatalf("invalid json %q: %v", buffer.String(), err)
+	}
+	ids := customerIds(customers)
+	if len(ids) != 3 || ids[0] != "arya" || ids[2] != "nafis" {
+		t.Fatalf("expected [arya budi nafis], got %v", ids)
+	}
+}
+
+func TestStreamExportNDJSON(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedExportCustomers(t, db)
+
+	var buffer bytes.Buffer
+	err := NewCustomerRepository(db).StreamExport(context.Background(), &buffer, "ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buffer.String())
+	}
+	for _, line := range lines {
+		customer := entity.Customer{}
+		if err := json.Unmarshal([]byte(line), &customer); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+	}
+}
+
+func TestStreamExportUnknownFormat(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+
+	err := NewCustomerRepository(db).StreamExport(context.Background(), &bytes.Buffer{}, "xml")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+// cancelWriter membatalkan context setelah baris pertama di-flush
+type cancelWriter struct {
+	bytes.Buffer
+	cancel  func()
+	flushes int
+}
+
+func (writer *cancelWriter) Flush() error {
+	writer.flushes++
+	writer.cancel()
+	return nil
+}
+
+func TestStreamExportCancelled(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedExportCustomers(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	writer := &cancelWriter{cancel: cancel}
+	err := NewCustomerRepository(db).StreamExport(ctx, writer, "ndjson")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if lines := strings.Count(writer.String(), "\n"); lines != 1 {
+		t.Fatalf("expected to stop after the first row, got %d rows", lines)
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"io"
+	"net/http"
+)
+
+func flush(w io.Writer) error {
+	switch flusher := w.(type) {
+	case interface{ Flush() error }:
+		return flusher.Flush()
+	case http.Flusher:
+		flusher.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const customerIdPrefix = "cus_"
+
+var idCounter atomic.Uint64
+
+// GenerateId returns prefix followed by the time in milliseconds, a per process
+// counter and 8 random bytes. It is safe to call from many goroutines.
+func GenerateId(prefix string) string {
+	random := make([]byte, 8)
+	rand.Read(random)
+	return prefix + strconv.FormatInt(time.Now().UnixMilli(), 36) +
+		strconv.FormatUint(idCounter.Add(1), 36) + hex.EncodeToString(random)
+}
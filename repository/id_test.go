@@ -0,0 +1,61 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGenerateIdUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		id := GenerateId("usr_")
+		if !strings.HasPrefix(id, "usr_") || seen[id] {
+			t.Fatalf("expected a new id with prefix usr_, got %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestInsertGeneratesIdConcurrently(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+
+	var group sync.WaitGroup
+	var mutex sync.Mutex
+	ids := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			customer, err := customerRepository.Insert(context.Background(), entity.Customer{Name: "Customer"})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mutex.Lock()
+			ids[customer.Id] = true
+			mutex.Unlock()
+		}()
+	}
+	group.Wait()
+
+	if len(ids) != 50 {
+		t.Fatalf("expected 50 unique ids, got %d", len(ids))
+	}
+	for id := range ids {
+		if !strings.HasPrefix(id, customerIdPrefix) {
+			t.Fatalf("expected id with prefix %s, got %s", customerIdPrefix, id)
+		}
+	}
+	total, err := customerRepository.Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 50 {
+		t.Fatalf("expected 50 stored customers, got %d", total)
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// quoteIdent wraps a table or column name in backticks so it can be interpolated into SQL
+func quoteIdent(name string) (string, error) {
+	if !identPattern.MatchString(name) {
+		return "", fmt.Errorf("invalid identifier %q", name)
+	}
+	return "`" + name + "`", nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "testing"
+
+func TestQuoteIdent(t *testing.T) {
+	quoted, err := quoteIdent("created_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quoted != "`created_at`" {
+		t.Fatalf("expected `created_at`, got %s", quoted)
+	}
+}
+
+func TestQuoteIdentRejectsSuspicious(t *testing.T) {
+	for _, name := range []string{"drop; --", "", "1name", "name`", "customer.id", "na me"} {
+		if _, err := quoteIdent(name); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+// LoggingDB wraps a DBTX and logs every statement with its duration. Argument
+// values are hidden unless LogArgs is set.
+type LoggingDB struct {
+	DB     DBTX
+	Logger *log.Logger
+	// LogArgs also logs the argument values. Only for local debugging, NEVER enable it
+	// in production: the values include secrets such as password hashes and personal data.
+	LogArgs bool
+}
+
+func NewLoggingDB(db DBTX, logger *log.Logger) *LoggingDB {
+	return &LoggingDB{DB: db, Logger: logger}
+}
+
+func (db *LoggingDB) log(query string, args []any, start time.Time, err error) {
+	if db.LogArgs {
+		db.Logger.Printf("%s args=%v (%s) err=%v", query, args, time.Since(start), err)
+		return
+	}
+	db.Logger.Printf("%s args=%d (%s) err=%v", query, len(args), time.Since(start), err)
+}
+
+func (db *LoggingDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	db.log(query, args, start, err)
+	return result, err
+}
+
+func (db *LoggingDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	db.log(query, args, start, err)
+	return rows, err
+}
+
+// QueryRowContext can not see the error until Scan, so it is logged as nil
+func (db *LoggingDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	db.log(query, args, start, nil)
+	return row
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLoggingDBHidesArgs(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	var output bytes.Buffer
+	loggingDB := NewLoggingDB(db, log.New(&output, "", 0))
+
+	_, err := loggingDB.ExecContext(context.Background(), "INSERT INTO user(username, password) VALUES (?, ?)", "admin", "rahasia123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "INSERT INTO user") || !strings.Contains(output.String(), "args=2") {
+		t.Fatalf("expected the statement and arg count to be logged, got %q", output.String())
+	}
+	if strings.Contains(output.String(), "rahasia123") || strings.Contains(output.String(), "admin") {
+		t.Fatalf("expected arg values to be hidden, got %q", output.String())
+	}
+}
+
+func TestLoggingDBLogArgs(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	var output bytes.Buffer
+	loggingDB := NewLoggingDB(db, log.New(&output, "", 0))
+	loggingDB.LogArgs = true
+
+	_, err := QueryScalar[int](context.Background(), loggingDB, "SELECT COUNT(*) FROM user WHERE username = ?", "fathir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "fathir") {
+		t.Fatalf("expected arg values to be logged, got %q", output.String())
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultNameRules is the pipeline applied to customer names when Config.NormalizeNames is set
+var DefaultNameRules = []func(string) string{strings.TrimSpace, CollapseSpaces, TitleCase}
+
+// NormalizeString applies rules to s in order
+func NormalizeString(s string, rules ...func(string) string) string {
+	for _, rule := range rules {
+		s = rule(s)
+	}
+	return s
+}
+
+// CollapseSpaces replaces every run of whitespace with a single space
+func CollapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// TitleCase upper cases the first letter of every word and leaves the rest as is
+func TitleCase(s string) string {
+	var builder strings.Builder
+	builder.Grow(len(s))
+	startOfWord := true
+	for _, r := range s {
+		if startOfWord {
+			r = unicode.ToUpper(r)
+		}
+		builder.WriteRune(r)
+		startOfWord = unicode.IsSpace(r)
+	}
+	return builder.String()
+}
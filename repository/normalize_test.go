@@ -0,0 +1,81 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeString(t *testing.T) {
+	if name := NormalizeString("  nafis   arya ", DefaultNameRules...); name != "Nafis Arya" {
+		t.Fatalf("expected Nafis Arya, got %q", name)
+	}
+	if name := NormalizeString("  nafis   arya ", strings.TrimSpace); name != "nafis   arya" {
+		t.Fatalf("expected only the outer spaces to be trimmed, got %q", name)
+	}
+	if name := NormalizeString("  nafis   arya "); name != "  nafis   arya " {
+		t.Fatalf("expected no rules to keep the input, got %q", name)
+	}
+}
+
+func TestCustomerNormalizeNames(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	config := belajar_golang_database.DefaultConfig()
+	config.NormalizeNames = true
+	customer, err := NewCustomerRepositoryWithConfig(db, config).Insert(ctx, entity.Customer{Id: "nafis", Name: "  nafis   arya "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != "Nafis Arya" {
+		t.Fatalf("expected Nafis Arya, got %q", customer.Name)
+	}
+
+	// tanpa NormalizeNames nama disimpan apa adanya
+	customer, err = NewCustomerRepository(db).Insert(ctx, entity.Customer{Id: "arya", Name: "  nafis   arya "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := NewCustomerRepository(db).FindById(ctx, "arya")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Name != "  nafis   arya " || stored.Name != customer.Name {
+		t.Fatalf("expected the name to be kept, got %q", stored.Name)
+	}
+}
+
+func TestCustomerNormalizeNamesOnEveryInsertPath(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+	seedCustomers(t, db, entity.Customer{Id: "sumber", Name: "  nafis   arya "})
+
+	config := belajar_golang_database.DefaultConfig()
+	config.NormalizeNames = true
+	customerRepository := NewCustomerRepositoryWithConfig(db, config)
+	if _, err := customerRepository.Onboard(ctx, entity.Customer{Id: "onboard", Name: "  nafis   arya "}, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := customerRepository.InsertIdempotent(ctx, "key-1", entity.Customer{Id: "idempotent", Name: "  nafis   arya "}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := customerRepository.FindOrCreate(ctx, entity.Customer{Id: "created", Name: "  nafis   arya "}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := customerRepository.CloneCustomer(ctx, "sumber", "clone"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"onboard", "idempotent", "created", "clone"} {
+		customer, err := customerRepository.FindById(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if customer.Name != "Nafis Arya" {
+			t.Fatalf("expected %s to be stored as Nafis Arya, got %q", id, customer.Name)
+		}
+	}
+}
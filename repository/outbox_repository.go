@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"belajar-golang-database/entity"
+	"context"
+)
+
+type OutboxRepository interface {
+	FetchUnpublished(ctx context.Context, limit int) ([]entity.OutboxEvent, error)
+	MarkPublished(ctx context.Context, ids []int64) (int64, error)
+}
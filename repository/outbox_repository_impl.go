@@ -0,0 +1,86 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const (
+	topicCustomerCreated  = "customer.created"
+	topicCustomerUpdated  = "customer.updated"
+	topicCustomerArchived = "customer.archived"
+	topicCustomerMerged   = "customer.merged"
+)
+
+// customerMergedEvent is the customer.merged payload, Merged lists the deleted duplicates
+type customerMergedEvent struct {
+	Email    string   `json:"email"`
+	Survivor string   `json:"survivor"`
+	Merged   []string `json:"merged"`
+	Balance  int64    `json:"balance"`
+}
+
+type outboxRepositoryImpl struct {
+	DB    *sql.DB
+	Clock belajar_golang_database.Clock
+}
+
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return NewOutboxRepositoryWithConfig(db, belajar_golang_database.DefaultConfig())
+}
+
+// NewOutboxRepositoryWithConfig stamps published_at with config.Clock, like the customer repository does
+func NewOutboxRepositoryWithConfig(db *sql.DB, config belajar_golang_database.Config) OutboxRepository {
+	clock := config.Clock
+	if clock == nil {
+		clock = belajar_golang_database.RealClock
+	}
+	return &outboxRepositoryImpl{DB: db, Clock: clock}
+}
+
+// writeOutbox records an event on tx, so it is committed or rolled back together with the change
+func writeOutbox(ctx context.Context, tx *sql.Tx, topic string, payload any, now time.Time) error {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO outbox(topic, payload, created_at) VALUES (?,?,?)", topic, string(bytes), now.UTC())
+	return err
+}
+
+func (repository *outboxRepositoryImpl) FetchUnpublished(ctx context.Context, limit int) ([]entity.OutboxEvent, error) {
+	script := "SELECT id, topic, payload, created_at, published_at FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT ?"
+	return Collect(ctx, repository.DB, script, func(rows *sql.Rows) (entity.OutboxEvent, error) {
+		event := entity.OutboxEvent{}
+		err := rows.Scan(&event.Id, &event.Topic, &event.Payload, &event.CreatedAt, &event.PublishedAt)
+		return event, err
+	}, limit)
+}
+
+// MarkPublished stamps the events as published, events published earlier keep their first timestamp
+func (repository *outboxRepositoryImpl) MarkPublished(ctx context.Context, ids []int64) (int64, error) {
+	var total int64
+	for _, chunk := range Chunk(ids, maxPlaceholders-1) {
+		args := make([]any, 0, len(chunk)+1)
+		args = append(args, repository.Clock.Now().UTC())
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+
+		script := "UPDATE outbox SET published_at = ? WHERE published_at IS NULL AND id IN (" + placeholders(len(chunk)) + ")"
+		result, err := repository.DB.ExecContext(ctx, script, args...)
+		if err != nil {
+			return total, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += affected
+	}
+	return total, nil
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestOutboxWrittenWithTransaction(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	customerRepository := NewCustomerRepository(db)
+	outboxRepository := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := customerRepository.InsertTx(ctx, tx, entity.Customer{Id: "nafis", Name: "Nafis"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := outboxRepository.FetchUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no event after rollback, got %+v", events)
+	}
+
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := customerRepository.InsertTx(ctx, tx, entity.Customer{Id: "arya", Name: "Arya"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err = outboxRepository.FetchUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Topic != topicCustomerCreated {
+		t.Fatalf("expected one customer.created event, got %+v", events)
+	}
+	customer := entity.Customer{}
+	if err := json.Unmarshal([]byte(events[0].Payload), &customer); err != nil || customer.Id != "arya" {
+		t.Fatalf("expected the payload to hold arya, got %q (%v)", events[0].Payload, err)
+	}
+
+	published, err := outboxRepository.MarkPublished(ctx, []int64{events[0].Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err = outboxRepository.FetchUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if published != 1 || len(events) != 0 {
+		t.Fatalf("expected the event to be published, got %d published and %d pending", published, len(events))
+	}
+}
+
+func TestOutboxUsesConfiguredClock(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	config := belajar_golang_database.DefaultConfig()
+	config.Clock = fixedClock{now: now}
+	customer, err := NewCustomerRepositoryWithConfig(db, config).Onboard(ctx, entity.Customer{Id: "nafis", Name: "Nafis"}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outboxRepository := NewOutboxRepositoryWithConfig(db, config)
+	events, err := outboxRepository.FetchUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || !events[0].CreatedAt.Equal(customer.CreatedAt) {
+		t.Fatalf("expected one event created with the customer at %s, got %+v", customer.CreatedAt, events)
+	}
+
+	config.Clock = fixedClock{now: now.Add(time.Hour)}
+	if _, err := NewOutboxRepositoryWithConfig(db, config).MarkPublished(ctx, []int64{events[0].Id}); err != nil {
+		t.Fatal(err)
+	}
+	publishedAt, err := QueryScalar[time.Time](ctx, db, "SELECT published_at FROM outbox WHERE id = ?", events[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !publishedAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected published_at from the Clock, got %s", publishedAt)
+	}
+}
+
+func TestOutboxWrittenWithUpdate(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	customerRepository := NewCustomerRepository(db)
+	outboxRepository := NewOutboxRepository(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// chunk pertama sudah menulis event, lalu batch dibatalkan sebelum chunk kedua
+	original := afterBatchChunk
+	afterBatchChunk = cancel
+	t.Cleanup(func() { afterBatchChunk = original })
+
+	customers := []entity.Customer{{Id: "nafis", Name: "Nafis Rollback"}}
+	for i := 0; i < updateBatchSize; i++ {
+		customers = append(customers, entity.Customer{Id: "missing" + strconv.Itoa(i), Name: "Missing"})
+	}
+	if err := customerRepository.UpdateBatch(ctx, customers); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	afterBatchChunk = original
+
+	events, err := outboxRepository.FetchUnpublished(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no event after rollback, got %+v", events)
+	}
+
+	updated, err := customerRepository.UpdateAndFetch(context.Background(), entity.Customer{Id: "nafis", Name: "Nafis Commit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err = outboxRepository.FetchUnpublished(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Topic != topicCustomerUpdated {
+		t.Fatalf("expected one customer.updated event, got %+v", events)
+	}
+	customer := entity.Customer{}
+	if err := json.Unmarshal([]byte(events[0].Payload), &customer); err != nil || customer.Name != updated.Name {
+		t.Fatalf("expected the payload to hold %q, got %q (%v)", updated.Name, events[0].Payload, err)
+	}
+}
+
+func TestOutboxTopicsPerMutation(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	email := sql.NullString{String: "nafis@example.com", Valid: true}
+	seedCustomers(t, db,
+		entity.Customer{Id: "nafis", Name: "Nafis", Email: email, Balance: 100},
+		entity.Customer{Id: "nafis2", Name: "Nafis", Email: email, Balance: 50},
+		entity.Customer{Id: "arya", Name: "Arya"},
+	)
+	customerRepository := NewCustomerRepository(db)
+	ctx := context.Background()
+
+	if _, err := customerRepository.CloneCustomer(ctx, "arya", "arya2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := customerRepository.FindOrCreate(ctx, entity.Customer{Id: "rully", Name: "Rully"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := customerRepository.InsertIdempotent(ctx, "key-1", entity.Customer{Id: "joko", Name: "Joko"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := customerRepository.MergeByEmail(ctx, email.String); err != nil {
+		t.Fatal(err)
+	}
+	if err := customerRepository.Archive(ctx, "arya"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := NewOutboxRepository(db).FetchUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var topics []string
+	for _, event := range events {
+		topics = append(topics, event.Topic)
+	}
+	expected := []string{topicCustomerCreated, topicCustomerCreated, topicCustomerCreated, topicCustomerMerged, topicCustomerArchived}
+	if !slices.Equal(topics, expected) {
+		t.Fatalf("expected topics %v, got %v", expected, topics)
+	}
+
+	merged := customerMergedEvent{}
+	if err := json.Unmarshal([]byte(events[3].Payload), &merged); err != nil {
+		t.Fatal(err)
+	}
+	if merged.Survivor != "nafis" || !slices.Equal(merged.Merged, []string{"nafis2"}) || merged.Balance != 150 {
+		t.Fatalf("unexpected merge payload %+v", merged)
+	}
+}
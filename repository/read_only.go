@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// query yang boleh lewat ReadOnly, selain itu ditolak
+var readKeywords = map[string]bool{
+	"SELECT": true, "SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true,
+}
+
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+}
+
+type readOnlyDB struct {
+	db *sql.DB
+}
+
+// ReadOnly wraps db so every ExecContext and every query that is not a SELECT,
+// SHOW, EXPLAIN or DESCRIBE fails with ErrReadOnly
+func ReadOnly(db *sql.DB) DBTX {
+	return &readOnlyDB{db: db}
+}
+
+type sqlWord struct {
+	text  string
+	depth int
+}
+
+// sqlWords lists the upper cased keywords and identifiers of query with their
+// parenthesis depth, skipping comments and quoted strings or identifiers
+func sqlWords(query string) []sqlWord {
+	var words []sqlWord
+	depth := 0
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case strings.HasPrefix(query[i:], "/*!"):
+			// komentar /*! ... */ dijalankan MySQL, isinya ikut diperiksa
+			i += 3
+			for i < len(query) && unicode.IsDigit(rune(query[i])) {
+				i++
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return words
+			}
+			i += end + 4
+		case c == '#' || strings.HasPrefix(query[i:], "-- ") || strings.HasPrefix(query[i:], "--\t") ||
+			strings.HasPrefix(query[i:], "--\n") || query[i:] == "--":
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return words
+			}
+			i += end + 1
+		case c == '\'' || c == '"' || c == '`':
+			i++
+			for i < len(query) && query[i] != c {
+				if query[i] == '\\' && c != '`' {
+					i++
+				}
+				i++
+			}
+			i++
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+		case c == '_' || unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(query) && (query[i] == '_' || query[i] == '$' || unicode.IsLetter(rune(query[i])) || unicode.IsDigit(rune(query[i]))) {
+				i++
+			}
+			words = append(words, sqlWord{text: strings.ToUpper(query[start:i]), depth: depth})
+		default:
+			i++
+		}
+	}
+	return words
+}
+
+// checkReadOnly only lets read statements through. The verb is found after leading
+// comments and parentheses, and after the CTEs of a WITH statement.
+func checkReadOnly(query string) error {
+	words := sqlWords(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	verb := words[0]
+	if verb.text == "WITH" {
+		// verb utama ada di kedalaman yang sama dengan WITH, setelah semua CTE
+		verb = sqlWord{}
+		for _, word := range words[1:] {
+			if word.depth == words[0].depth && (readKeywords[word.text] || writeKeywords[word.text]) {
+				verb = word
+				break
+			}
+		}
+	}
+	if !readKeywords[verb.text] {
+		return fmt.Errorf("%w: %s", ErrReadOnly, verb.text)
+	}
+
+	// EXPLAIN ANALYZE benar-benar menjalankan statement yang dijelaskan
+	if verb.text != "SELECT" && verb.text != "SHOW" {
+		for _, word := range words {
+			if writeKeywords[word.text] {
+				return fmt.Errorf("%w: %s %s", ErrReadOnly, verb.text, word.text)
+			}
+		}
+	}
+	return nil
+}
+
+func (db *readOnlyDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, fmt.Errorf("%w: exec is not allowed", ErrReadOnly)
+}
+
+func (db *readOnlyDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if err := checkReadOnly(query); err != nil {
+		return nil, err
+	}
+	return db.db.QueryContext(ctx, query, args...)
+}
+
+// rejectedContext is already done with ErrReadOnly as its error. *sql.Row can not
+// be built with an error from outside database/sql, but it reports ctx.Err().
+type rejectedContext struct {
+	context.Context
+	err error
+}
+
+var closedDone = func() chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}()
+
+func (ctx rejectedContext) Done() <-chan struct{} {
+	return closedDone
+}
+
+func (ctx rejectedContext) Err() error {
+	return ctx.err
+}
+
+func (db *readOnlyDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if err := checkReadOnly(query); err != nil {
+		return db.db.QueryRowContext(rejectedContext{Context: ctx, err: err}, query, args...)
+	}
+	return db.db.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	seedCustomers(t, db, entity.Customer{Id: "nafis", Name: "Nafis"})
+	readOnly := ReadOnly(db)
+	ctx := context.Background()
+
+	total, err := QueryScalar[int](ctx, readOnly, "SELECT COUNT(*) FROM customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("expected SELECT to pass through, got count %d", total)
+	}
+
+	_, err = readOnly.ExecContext(ctx, "INSERT INTO customer(id, name) VALUES (?, ?)", "arya", "Arya")
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+
+	_, err = readOnly.QueryContext(ctx, "  \n update customer SET name = 'x'")
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+
+	var name string
+	err = readOnly.QueryRowContext(ctx, "DELETE FROM customer").Scan(&name)
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+
+	name, err = QueryScalar[string](ctx, db, "SELECT name FROM customer WHERE id = ?", "nafis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Nafis" {
+		t.Fatalf("expected the row to be untouched, got %s", name)
+	}
+}
+
+func TestCheckReadOnly(t *testing.T) {
+	allowed := []string{
+		"SELECT * FROM customer",
+		"  /* laporan */ select name FROM customer WHERE status = 'update'",
+		"(SELECT id FROM customer) UNION (SELECT id FROM customer_archive)",
+		"WITH aktif AS (SELECT * FROM customer WHERE status = 'active') SELECT COUNT(*) FROM aktif",
+		"SHOW TABLES",
+		"EXPLAIN SELECT * FROM customer",
+		"DESCRIBE customer",
+		"-- komentar\nSELECT 1",
+	}
+	for _, query := range allowed {
+		if err := checkReadOnly(query); err != nil {
+			t.Fatalf("expected %q to be allowed, got %v", query, err)
+		}
+	}
+
+	rejected := []string{
+		"/* c */ UPDATE customer SET name = 'x'",
+		"# c\nDELETE FROM customer",
+		"-- c\nDELETE FROM customer",
+		"(UPDATE customer SET name = 'x')",
+		"WITH x AS (SELECT id FROM customer) DELETE FROM customer WHERE id IN (SELECT id FROM x)",
+		"WITH RECURSIVE x (n) AS (SELECT 1) UPDATE customer SET balance = 0",
+		"/*!50000 UPDATE customer SET name = 'x' */",
+		"EXPLAIN ANALYZE DELETE FROM customer",
+		"SET SESSION sql_mode = ''",
+		"CALL reset_customers()",
+		"LOCK TABLES customer WRITE",
+		"GRANT ALL ON *.* TO 'nafis'",
+	}
+	for _, query := range rejected {
+		if err := checkReadOnly(query); !errors.Is(err, ErrReadOnly) {
+			t.Fatalf("expected %q to be rejected, got %v", query, err)
+		}
+	}
+}
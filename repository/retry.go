@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	retryBase = 50 * time.Millisecond
+	retryMax  = 2 * time.Second
+
+	mysqlErrDeadlock = 1213
+)
+
+// diganti di test supaya jitter-nya deterministik
+var randInt64N = rand.Int64N
+
+// backoff returns a random delay between 0 and min(max, base*2^attempt)
+func backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	ceiling := base
+	for i := 0; i < attempt && ceiling < max; i++ {
+		ceiling *= 2
+	}
+	if ceiling > max {
+		ceiling = max
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(randInt64N(int64(ceiling) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDeadlock
+}
+
+// RetryBudget bounds a retry loop by attempts and by total time. The context
+// deadline, when there is one, bounds it as well.
+type RetryBudget struct {
+	MaxAttempts int           // 0 or less means a single attempt
+	MaxDuration time.Duration // 0 means only the attempts and the context deadline count
+}
+
+// next reports whether another attempt may start after waiting delay
+func (budget RetryBudget) next(ctx context.Context, attempts int, start time.Time, delay time.Duration) bool {
+	if attempts >= budget.MaxAttempts {
+		return false
+	}
+	if budget.MaxDuration > 0 && time.Since(start)+delay > budget.MaxDuration {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+	return true
+}
+
+// retry calls fn until it succeeds, fails with an error retryable rejects, or the
+// budget runs out. The last error is then wrapped with the number of attempts.
+func retry(ctx context.Context, budget RetryBudget, retryable func(error) bool, fn func() error) error {
+	start := time.Now()
+	for attempts := 1; ; attempts++ {
+		err := fn()
+		if err == nil || !retryable(err) {
+			return err
+		}
+
+		delay := backoff(attempts-1, retryBase, retryMax)
+		if !budget.next(ctx, attempts, start, delay) {
+			return fmt.Errorf("after %d attempts: %w", attempts, err)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return fmt.Errorf("after %d attempts: %w", attempts, err)
+		}
+	}
+}
+
+// WaitForDB pings db until it answers, waiting with backoff between attempts
+func WaitForDB(ctx context.Context, db *sql.DB, budget RetryBudget) error {
+	return retry(ctx, budget, func(err error) bool { return ctx.Err() == nil }, func() error {
+		return db.PingContext(ctx)
+	})
+}
+
+// RetryOnDeadlock calls fn again when MySQL picked it as a deadlock victim
+func RetryOnDeadlock(ctx context.Context, budget RetryBudget, fn func() error) error {
+	return retry(ctx, budget, isDeadlock, fn)
+}
+
+// RunInTxWithRetry runs fn in a new transaction, retrying the whole transaction on deadlock
+func RunInTxWithRetry(ctx context.Context, db *sql.DB, budget RetryBudget, fn func(tx *sql.Tx) error) error {
+	return RetryOnDeadlock(ctx, budget, func() error {
+		return WithTransaction(ctx, db, fn)
+	})
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func withRand(t *testing.T, fn func(n int64) int64) {
+	original := randInt64N
+	randInt64N = fn
+	t.Cleanup(func() { randInt64N = original })
+}
+
+func TestBackoffGrowsWithAttempt(t *testing.T) {
+	// selalu ambil batas atas supaya pertumbuhannya terlihat
+	withRand(t, func(n int64) int64 { return n - 1 })
+
+	previous := time.Duration(-1)
+	for attempt := 0; attempt < 5; attempt++ {
+		delay := backoff(attempt, 10*time.Millisecond, time.Second)
+		if delay <= previous {
+			t.Fatalf("attempt %d: expected delay to grow, got %s after %s", attempt, delay, previous)
+		}
+		previous = delay
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	withRand(t, rng.Int64N)
+
+	for attempt := 0; attempt < 100; attempt++ {
+		delay := backoff(attempt, 10*time.Millisecond, 500*time.Millisecond)
+		if delay < 0 || delay > 500*time.Millisecond {
+			t.Fatalf("attempt %d: delay %s out of bounds", attempt, delay)
+		}
+	}
+}
+
+func TestBackoffDeterministic(t *testing.T) {
+	delays := func() []time.Duration {
+		rng := rand.New(rand.NewPCG(7, 7))
+		withRand(t, rng.Int64N)
+		var delays []time.Duration
+		for attempt := 0; attempt < 5; attempt++ {
+			delays = append(delays, backoff(attempt, 10*time.Millisecond, time.Second))
+		}
+		return delays
+	}
+
+	first, second := delays(), delays()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("expected the same seed to give the same delays, got %v and %v", first, second)
+		}
+	}
+}
+
+func TestRetryOnDeadlock(t *testing.T) {
+	withRand(t, func(n int64) int64 { return 0 })
+
+	calls := 0
+	err := RetryOnDeadlock(context.Background(), RetryBudget{MaxAttempts: 5}, func() error {
+		calls++
+		if calls < 3 {
+			return &mysql.MySQLError{Number: mysqlErrDeadlock, Message: "Deadlock found"}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+
+	failure := errors.New("bukan deadlock")
+	calls = 0
+	err = RetryOnDeadlock(context.Background(), RetryBudget{MaxAttempts: 5}, func() error {
+		calls++
+		return failure
+	})
+	if !errors.Is(err, failure) || calls != 1 {
+		t.Fatalf("expected other errors to return immediately, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryBudgetExhausted(t *testing.T) {
+	withRand(t, func(n int64) int64 { return 0 })
+
+	calls := 0
+	err := RetryOnDeadlock(context.Background(), RetryBudget{MaxAttempts: 3}, func() error {
+		calls++
+		return &mysql.MySQLError{Number: mysqlErrDeadlock, Message: "Deadlock found"}
+	})
+	if calls != 3 {
+		t.Fatalf("expected exactly 3 calls, got %d", calls)
+	}
+	if !isDeadlock(err) || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("expected the deadlock wrapped with the attempts, got %v", err)
+	}
+}
+
+func TestRetryBudgetMaxDuration(t *testing.T) {
+	// selalu menunggu batas atas backoff, jadi MaxDuration yang menghentikan loop
+	withRand(t, func(n int64) int64 { return n - 1 })
+
+	calls := 0
+	err := RetryOnDeadlock(context.Background(), RetryBudget{MaxAttempts: 100, MaxDuration: 20 * time.Millisecond}, func() error {
+		calls++
+		return &mysql.MySQLError{Number: mysqlErrDeadlock, Message: "Deadlock found"}
+	})
+	if !isDeadlock(err) || calls >= 100 {
+		t.Fatalf("expected MaxDuration to stop the retries, got %v after %d calls", err, calls)
+	}
+}
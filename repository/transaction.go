@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WithTransaction runs fn inside a transaction, committing when fn returns nil
+// and rolling back otherwise. The optional onComplete callbacks are called once
+// the transaction is resolved with the outcome, its duration and the final error.
+func WithTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error, onComplete ...func(committed bool, d time.Duration, err error)) (err error) {
+	start := time.Now()
+	committed := false
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("transaction panicked: %v", recovered)
+			defer panic(recovered)
+		}
+		for _, callback := range onComplete {
+			callback(committed, time.Since(start), err)
+		}
+	}()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	committed = err == nil
+	return err
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithTransactionCommitted(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	calls := 0
+	err := WithTransaction(ctx, db, func(tx *sql.Tx) error {
+		_, err := insertCustomer(ctx, tx, "customer", entity.Customer{Id: "nafis", Name: "Nafis"}, time.Now())
+		return err
+	}, func(committed bool, d time.Duration, err error) {
+		calls++
+		if !committed || err != nil || d <= 0 {
+			t.Errorf("expected committed with no error, got committed=%v d=%s err=%v", committed, d, err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected callback to fire once, got %d", calls)
+	}
+	if exists, _ := NewCustomerRepository(db).Exists(ctx, "nafis"); !exists {
+		t.Fatal("expected customer to be committed")
+	}
+}
+
+func TestWithTransactionRolledBack(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+	ctx := context.Background()
+
+	failure := errors.New("gagal")
+	calls := 0
+	var reported error
+	err := WithTransaction(ctx, db, func(tx *sql.Tx) error {
+		_, err := insertCustomer(ctx, tx, "customer", entity.Customer{Id: "nafis", Name: "Nafis"}, time.Now())
+		if err != nil {
+			return err
+		}
+		return failure
+	}, func(committed bool, d time.Duration, err error) {
+		calls++
+		if committed {
+			t.Error("expected rolled back transaction")
+		}
+		reported = err
+	})
+	if !errors.Is(err, failure) || !errors.Is(reported, failure) {
+		t.Fatalf("expected the error to be propagated, got %v and %v", err, reported)
+	}
+	if calls != 1 {
+		t.Fatalf("expected callback to fire once, got %d", calls)
+	}
+	if exists, _ := NewCustomerRepository(db).Exists(ctx, "nafis"); exists {
+		t.Fatal("expected customer to be rolled back")
+	}
+}
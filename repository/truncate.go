@@ -0,0 +1,60 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"context"
+	"errors"
+)
+
+// tabel yang boleh di-truncate, true jika namanya memakai TablePrefix
+var truncatableTables = map[string]bool{
+	belajar_golang_database.CustomerTable:        true,
+	belajar_golang_database.CustomerArchiveTable: true,
+	belajar_golang_database.UserTable:            true,
+	"balance_audit":                              false,
+	"comments":                                   false,
+	"outbox":                                     false,
+}
+
+func TruncateTables(ctx context.Context, db DBTX, tables ...string) (int64, error) {
+	return TruncateTablesWithConfig(ctx, db, belajar_golang_database.DefaultConfig(), tables...)
+}
+
+// TruncateTablesWithConfig takes the unprefixed table names and truncates the
+// tables named with config.TablePrefix
+func TruncateTablesWithConfig(ctx context.Context, db DBTX, config belajar_golang_database.Config, tables ...string) (int64, error) {
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		prefixed, ok := truncatableTables[table]
+		if !ok {
+			return 0, errors.New("table " + table + " can not be truncated")
+		}
+		if prefixed {
+			table = config.Table(table)
+		}
+		var err error
+		quoted[i], err = quoteIdent(table)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var total int64
+	for _, table := range quoted {
+		script := "TRUNCATE TABLE " + table
+		if DryRun {
+			affected, err := dryRunCount(ctx, db, script, "SELECT COUNT(*) FROM "+table)
+			if err != nil {
+				return total, err
+			}
+			total += affected
+			continue
+		}
+
+		_, err := db.ExecContext(ctx, script)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
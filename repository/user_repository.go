@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"belajar-golang-database/entity"
+	"context"
+)
+
+type UserRepository interface {
+	FindByUsername(ctx context.Context, username string) (entity.User, error)
+	FindAll(ctx context.Context, limit int, offset int) ([]entity.User, error)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const userColumns = "id, username, password, created_at"
+
+type userRepositoryImpl struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return NewUserRepositoryWithConfig(db, belajar_golang_database.DefaultConfig())
+}
+
+func NewUserRepositoryWithConfig(db *sql.DB, config belajar_golang_database.Config) UserRepository {
+	table := config.QuotedTable(belajar_golang_database.UserTable)
+	return &userRepositoryImpl{DB: db, Table: table}
+}
+
+func scanUser(rows *sql.Rows) (entity.User, error) {
+	user := entity.User{}
+	err := rows.Scan(&user.Id, &user.Username, &user.PasswordHash, &user.CreatedAt)
+	return user, err
+}
+
+func (repository *userRepositoryImpl) FindByUsername(ctx context.Context, username string) (entity.User, error) {
+	script := "SELECT " + userColumns + " FROM " + repository.Table + " WHERE username = ? LIMIT 1"
+	rows, err := repository.DB.QueryContext(ctx, script, username)
+	if err != nil {
+		return entity.User{}, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		// ada
+		return scanUser(rows)
+	} else {
+		// tidak ada
+		return entity.User{}, fmt.Errorf("username %s: %w", username, ErrNotFound)
+	}
+}
+
+func (repository *userRepositoryImpl) FindAll(ctx context.Context, limit int, offset int) ([]entity.User, error) {
+	script := "SELECT " + userColumns + " FROM " + repository.Table + " ORDER BY id LIMIT ? OFFSET ?"
+	return Collect(ctx, repository.DB, script, scanUser, limit, offset)
+}
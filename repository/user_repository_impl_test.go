@@ -0,0 +1,69 @@
+package repository
+
+import (
+	belajar_golang_database "belajar-golang-database"
+	"belajar-golang-database/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func seedUsers(t *testing.T, db *sql.DB, usernames ...string) {
+	t.Helper()
+	for _, username := range usernames {
+		_, err := db.Exec("INSERT INTO user(username, password) VALUES (?, ?)", username, "hash-"+username)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUserFindByUsername(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+
+	seedUsers(t, db, "admin", "fathir")
+	userRepository := NewUserRepository(db)
+
+	user, err := userRepository.FindByUsername(context.Background(), "fathir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "fathir" || user.PasswordHash != "hash-fathir" || user.CreatedAt.IsZero() {
+		t.Fatalf("unexpected user %+v", user)
+	}
+
+	_, err = userRepository.FindByUsername(context.Background(), "budi")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUserFindAll(t *testing.T) {
+	db, _ := belajar_golang_database.SetupTestDB(t)
+
+	seedUsers(t, db, "admin", "fathir", "nafis")
+	userRepository := NewUserRepository(db)
+
+	users, err := userRepository.FindAll(context.Background(), 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Username != "fathir" || users[1].Username != "nafis" {
+		t.Fatalf("unexpected users %+v", users)
+	}
+}
+
+func TestUserJsonOmitsPasswordHash(t *testing.T) {
+	user := entity.User{Id: 1, Username: "admin", PasswordHash: "rahasia"}
+
+	bytes, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(bytes), "rahasia") || strings.Contains(string(bytes), "PasswordHash") {
+		t.Fatalf("password hash leaked into json: %s", bytes)
+	}
+}
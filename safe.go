@@ -0,0 +1,33 @@
+package belajargolangdatabase
+
+import "fmt"
+
+// PanicError carries the value of a panic recovered by SafeExec or SafeQuery
+type PanicError struct {
+	Value any
+}
+
+func (err PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", err.Value)
+}
+
+// SafeExec runs fn and turns a panic inside it into a returned PanicError,
+// so code written in the panic(err) style can not crash a server
+func SafeExec(fn func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = PanicError{Value: recovered}
+		}
+	}()
+	return fn()
+}
+
+func SafeQuery[T any](fn func() (T, error)) (result T, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			var zero T
+			result, err = zero, PanicError{Value: recovered}
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,43 @@
+package belajargolangdatabase
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSafeExecRecoversPanic(t *testing.T) {
+	err := SafeExec(func() error {
+		panic("koneksi putus")
+	})
+
+	var panicErr PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "koneksi putus" {
+		t.Fatalf("expected PanicError carrying the panic value, got %v", err)
+	}
+}
+
+func TestSafeExecSuccess(t *testing.T) {
+	err := SafeExec(func() error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func TestSafeQuery(t *testing.T) {
+	total, err := SafeQuery(func() (int, error) {
+		return 3, nil
+	})
+	if err != nil || total != 3 {
+		t.Fatalf("expected 3 and nil, got %d and %v", total, err)
+	}
+
+	total, err = SafeQuery(func() (int, error) {
+		panic(errors.New("scan gagal"))
+	})
+	var panicErr PanicError
+	if !errors.As(err, &panicErr) || total != 0 {
+		t.Fatalf("expected PanicError and zero value, got %d and %v", total, err)
+	}
+}
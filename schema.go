@@ -0,0 +1,80 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MySQL 8 tidak lagi menampilkan display width integer, kecuali tinyint(1)
+var displayWidthPattern = regexp.MustCompile(`^(smallint|mediumint|int|bigint)\(\d+\)`)
+
+func normalizeColumnType(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	return displayWidthPattern.ReplaceAllString(columnType, "$1")
+}
+
+// VerifySchema compares the live customer table against expected and lists every
+// missing, extra and type mismatched column in the returned error
+func VerifySchema(ctx context.Context, db *sql.DB, expected []ColumnInfo) error {
+	return VerifySchemaWithConfig(ctx, db, DefaultConfig(), expected)
+}
+
+// VerifySchemaWithConfig is VerifySchema for the customer table named with config.TablePrefix
+func VerifySchemaWithConfig(ctx context.Context, db *sql.DB, config Config, expected []ColumnInfo) error {
+	table := config.Table(CustomerTable)
+	actual, err := DescribeTable(ctx, db, table)
+	if err != nil {
+		return err
+	}
+
+	actualByName := map[string]ColumnInfo{}
+	for _, column := range actual {
+		actualByName[column.Name] = column
+	}
+
+	var problems []string
+	expectedNames := map[string]bool{}
+	for _, column := range expected {
+		expectedNames[column.Name] = true
+		found, ok := actualByName[column.Name]
+		if !ok {
+			problems = append(problems, "missing column "+column.Name)
+			continue
+		}
+		if normalizeColumnType(found.Type) != normalizeColumnType(column.Type) {
+			problems = append(problems, fmt.Sprintf("column %s is %s, expected %s", column.Name, found.Type, column.Type))
+		}
+	}
+	for _, column := range actual {
+		if !expectedNames[column.Name] {
+			problems = append(problems, "extra column "+column.Name)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("schema drift in %s: %s", table, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// DumpSchema returns the CREATE TABLE statements of the customer and user tables as MySQL reports them
+func DumpSchema(ctx context.Context, db *sql.DB) (string, error) {
+	return DumpSchemaWithConfig(ctx, db, DefaultConfig())
+}
+
+// DumpSchemaWithConfig is DumpSchema for the tables named with config.TablePrefix
+func DumpSchemaWithConfig(ctx context.Context, db *sql.DB, config Config) (string, error) {
+	var statements []string
+	for _, table := range []string{CustomerTable, UserTable} {
+		var name, statement string
+		err := db.QueryRowContext(ctx, "SHOW CREATE TABLE "+config.QuotedTable(table)).Scan(&name, &statement)
+		if err != nil {
+			return "", err
+		}
+		statements = append(statements, statement+";")
+	}
+	return strings.Join(statements, "\n\n"), nil
+}
@@ -0,0 +1,96 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func expectedCustomerSchema() []ColumnInfo {
+	return []ColumnInfo{
+		{Name: "id", Type: "varchar(100)"},
+		{Name: "name", Type: "varchar(100)"},
+		{Name: "email", Type: "varchar(100)"},
+		{Name: "balance", Type: "int"},
+		{Name: "rating", Type: "double"},
+		{Name: "created_at", Type: "timestamp"},
+		{Name: "birth_date", Type: "date"},
+		{Name: "married", Type: "tinyint(1)"},
+		{Name: "status", Type: "varchar(20)"},
+		{Name: "deleted_at", Type: "timestamp"},
+		{Name: "updated_at", Type: "timestamp(6)"},
+	}
+}
+
+func TestVerifySchema(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	err := VerifySchema(context.Background(), db, expectedCustomerSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifySchemaMissingColumn(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	expected := append(expectedCustomerSchema(), ColumnInfo{Name: "phone", Type: "varchar(20)"})
+	err := VerifySchema(context.Background(), db, expected)
+	if err == nil || !strings.Contains(err.Error(), "missing column phone") {
+		t.Fatalf("expected missing column phone, got %v", err)
+	}
+}
+
+func TestDumpSchema(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	schema, err := DumpSchema(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"CREATE TABLE `customer`", "CREATE TABLE `user`", "`balance`"} {
+		if !strings.Contains(schema, expected) {
+			t.Fatalf("expected schema to contain %s, got:\n%s", expected, schema)
+		}
+	}
+}
+
+func TestSchemaHelpersHonorTablePrefix(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	ctx := context.Background()
+	t.Cleanup(func() {
+		for _, table := range []string{"app_customer", "app_customer_archive", "app_user"} {
+			db.Exec("DROP TABLE IF EXISTS " + table)
+		}
+	})
+
+	config := DefaultConfig()
+	config.TablePrefix = "app_"
+	if err := MigrateWithConfig(ctx, db, config); err != nil {
+		t.Fatal(err)
+	}
+	// hanya tabel berprefix yang punya kolom salah ketik
+	if _, err := db.ExecContext(ctx, "ALTER TABLE app_customer CHANGE balance balannce INT DEFAULT 0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySchemaWithConfig(ctx, db, config, expectedCustomerSchema()); err == nil || !strings.Contains(err.Error(), "app_customer") {
+		t.Fatalf("expected drift reported on app_customer, got %v", err)
+	}
+	if err := RenameBalanceColumnWithConfig(ctx, db, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySchemaWithConfig(ctx, db, config, expectedCustomerSchema()); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := DumpSchemaWithConfig(ctx, db, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(schema, "CREATE TABLE `app_customer`") || !strings.Contains(schema, "CREATE TABLE `app_user`") {
+		t.Fatalf("expected the prefixed tables, got:\n%s", schema)
+	}
+	if err := OptimizeCustomerTableWithConfig(ctx, db, config); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -10,30 +10,28 @@ import (
 )
 
 func TestExecSql(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 
 	script := "INSERT INTO customer(id, name) VALUES('arya', 'Nafis') "
 	_, err := db.ExecContext(ctx, script)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	fmt.Println("Success Insert New Customer")
 }
 
 func TestQerySql(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 
 	script := "SELECT id, name FROM customer"
 	rows, err := db.QueryContext(ctx, script)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 	defer rows.Close()
 
@@ -41,7 +39,7 @@ func TestQerySql(t *testing.T) {
 		var id, name string
 		err := rows.Scan(&id, &name)
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 
 		fmt.Println("Id:", id)
@@ -50,30 +48,29 @@ func TestQerySql(t *testing.T) {
 }
 
 func TestQuerySqlComplex(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 
-	script := "SELECT id, name, email, balannce, rating, created_at, birth_date, married FROM customer"
+	script := "SELECT id, name, email, balance, rating, created_at, birth_date, married FROM customer"
 	rows, err := db.QueryContext(ctx, script)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var id, name string
 		var email sql.NullString
-		var balannce int32
+		var balance int32
 		var rating float64
 		var birthDate sql.NullTime
 		var created_at time.Time
 		var married bool
 
-		err := rows.Scan(&id, &name, &email, &balannce, &rating, &created_at, &birthDate, &married)
+		err := rows.Scan(&id, &name, &email, &balance, &rating, &created_at, &birthDate, &married)
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 
 		fmt.Println("==================")
@@ -83,7 +80,7 @@ func TestQuerySqlComplex(t *testing.T) {
 			fmt.Println("Email:", email.String)
 		}
 
-		fmt.Println("Balance:", balannce)
+		fmt.Println("Balance:", balance)
 		fmt.Println("Rating:", rating)
 		if birthDate.Valid {
 			fmt.Println("Birth Date:", birthDate.Time)
@@ -94,8 +91,7 @@ func TestQuerySqlComplex(t *testing.T) {
 }
 
 func TestSqlInjection(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 
@@ -106,7 +102,7 @@ func TestSqlInjection(t *testing.T) {
 	fmt.Println(script)
 	rows, err := db.QueryContext(ctx, script)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 	defer rows.Close()
 
@@ -114,7 +110,7 @@ func TestSqlInjection(t *testing.T) {
 		var username string
 		err := rows.Scan(&username)
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 		fmt.Println("Sukses login", username)
 	} else {
@@ -123,8 +119,7 @@ func TestSqlInjection(t *testing.T) {
 }
 
 func TestSqlInjectionSafe(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 
@@ -135,7 +130,7 @@ func TestSqlInjectionSafe(t *testing.T) {
 	fmt.Println(script)
 	rows, err := db.QueryContext(ctx, script, username, password)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 	defer rows.Close()
 
@@ -143,7 +138,7 @@ func TestSqlInjectionSafe(t *testing.T) {
 		var username string
 		err := rows.Scan(&username)
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 		fmt.Println("Sukses login", username)
 	} else {
@@ -152,8 +147,7 @@ func TestSqlInjectionSafe(t *testing.T) {
 }
 
 func TestExecSqlParameter(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 
@@ -163,15 +157,14 @@ func TestExecSqlParameter(t *testing.T) {
 	script := "INSERT INTO user(username, password) VALUES(?, ?) "
 	_, err := db.ExecContext(ctx, script, username, password)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	fmt.Println("Success Insert New user")
 }
 
 func TestAutoIncrement(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 
@@ -181,26 +174,25 @@ func TestAutoIncrement(t *testing.T) {
 	script := "INSERT INTO comments(email, comment) VALUES(?, ?) "
 	result, err := db.ExecContext(ctx, script, email, comment)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	insertId, err := result.LastInsertId()
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	fmt.Println("Success Insert New comment with id ", insertId)
 }
 
 func TestPrepareStatement(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 	script := "INSERT INTO comments(email, comment) VALUES(?, ?) "
 	statement, err := db.PrepareContext(ctx, script)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 	defer statement.Close()
 
@@ -209,12 +201,12 @@ func TestPrepareStatement(t *testing.T) {
 		comment := "Komentar ke" + strconv.Itoa(i)
 		result, err := statement.ExecContext(ctx, email, comment)
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 
 		id, err := result.LastInsertId()
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 		fmt.Println("Comment Id", id)
 	}
@@ -222,13 +214,12 @@ func TestPrepareStatement(t *testing.T) {
 }
 
 func TestTransaction(t *testing.T) {
-	db := GetConnection()
-	defer db.Close()
+	db, _ := SetupTestDB(t)
 
 	ctx := context.Background()
 	tx, err := db.Begin()
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	script := "INSERT INTO comments(email, comment) VALUES(?, ?) "
@@ -238,19 +229,19 @@ func TestTransaction(t *testing.T) {
 		comment := "Komentar ke" + strconv.Itoa(i)
 		result, err := tx.ExecContext(ctx, script, email, comment)
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 
 		id, err := result.LastInsertId()
 		if err != nil {
-			panic(err)
+			t.Fatal(err)
 		}
 		fmt.Println("Comment Id", id)
 	}
 
 	err = tx.Commit() //can use rollback too
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 }
@@ -0,0 +1,39 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type TableStats struct {
+	Rows       int64 // exact, from COUNT(*)
+	DataBytes  int64 // approximate, as reported by information_schema
+	IndexBytes int64 // approximate, as reported by information_schema
+}
+
+// GetTableStats reports the row count and approximate storage of table in the
+// current database. information_schema.tables.table_rows is only an estimate for
+// InnoDB, so the row count comes from COUNT(*) instead. SQLite has no
+// information_schema, the same sizes would come from the dbstat virtual table
+// (SELECT SUM(pgsize) FROM dbstat WHERE name = ?), this project only targets MySQL.
+func GetTableStats(ctx context.Context, db *sql.DB, table string) (TableStats, error) {
+	stats := TableStats{}
+	if !tableNamePattern.MatchString(table) {
+		return stats, fmt.Errorf("invalid table name %q", table)
+	}
+
+	script := "SELECT COALESCE(data_length, 0), COALESCE(index_length, 0) FROM information_schema.tables " +
+		"WHERE table_schema = DATABASE() AND table_name = ?"
+	err := db.QueryRowContext(ctx, script, table).Scan(&stats.DataBytes, &stats.IndexBytes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return stats, fmt.Errorf("table %q not found", table)
+	}
+	if err != nil {
+		return stats, err
+	}
+
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM `"+table+"`").Scan(&stats.Rows)
+	return stats, err
+}
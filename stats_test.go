@@ -0,0 +1,35 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetTableStats(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	ctx := context.Background()
+	for _, id := range []string{"satu", "dua", "tiga"} {
+		if _, err := db.ExecContext(ctx, "INSERT INTO customer(id, name) VALUES (?, ?)", id, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := GetTableStats(ctx, db, "customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM customer").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Rows != count {
+		t.Fatalf("expected %d rows, got %d", count, stats.Rows)
+	}
+	if stats.DataBytes < 0 || stats.IndexBytes < 0 {
+		t.Fatalf("expected non-negative sizes, got %+v", stats)
+	}
+
+	if _, err := GetTableStats(ctx, db, "customer; DROP TABLE customer"); err == nil {
+		t.Fatal("expected an invalid table name to be rejected")
+	}
+}
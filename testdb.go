@@ -0,0 +1,57 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testTables = []string{"comments", "customer", "balance_audit", "idempotency_keys", "user", "outbox", "customer_archive"}
+
+func truncateTestTables(ctx context.Context, db *sql.DB) error {
+	for _, table := range testTables {
+		_, err := db.ExecContext(ctx, "TRUNCATE TABLE `"+table+"`")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupTestDB connects to the test database, migrates it and starts with empty tables.
+// The returned cleanup truncates the tables and closes the pool, it is also registered
+// with t.Cleanup so calling it is optional.
+func SetupTestDB(t testing.TB) (*sql.DB, func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	db, err := Connect(ctx, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Migrate(ctx, db)
+	if err == nil {
+		err = truncateTestTables(ctx, db)
+	}
+	if err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			err := truncateTestTables(context.Background(), db)
+			if err != nil {
+				t.Error(err)
+			}
+			db.Close()
+		})
+	}
+	t.Cleanup(cleanup)
+	return db, cleanup
+}
@@ -0,0 +1,16 @@
+package belajargolangdatabase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetupTestDBCleanupClosesConnection(t *testing.T) {
+	db, cleanup := SetupTestDB(t)
+	cleanup()
+
+	_, err := db.Exec("SELECT 1")
+	if err == nil || !strings.Contains(err.Error(), "database is closed") {
+		t.Fatalf("expected database is closed, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrParseTimeDisabled = errors.New("DATETIME values are returned as []uint8, enable parseTime=true in your DSN")
+
+// CheckTimeSupport verifies that the DSN can scan MySQL timestamps into time.Time
+func CheckTimeSupport(ctx context.Context, db *sql.DB) error {
+	var now time.Time
+	err := db.QueryRowContext(ctx, "SELECT NOW()").Scan(&now)
+	if err != nil && strings.Contains(err.Error(), "[]uint8") {
+		return fmt.Errorf("%w: %v", ErrParseTimeDisabled, err)
+	}
+	return err
+}
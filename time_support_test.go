@@ -0,0 +1,38 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestCheckTimeSupportWithoutParseTime(t *testing.T) {
+	mysqlConfig, err := mysql.ParseDSN(DefaultConfig().DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mysqlConfig.ParseTime = false
+
+	db, err := sql.Open("mysql", mysqlConfig.FormatDSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = CheckTimeSupport(context.Background(), db)
+	if !errors.Is(err, ErrParseTimeDisabled) {
+		t.Fatalf("expected ErrParseTimeDisabled, got %v", err)
+	}
+}
+
+func TestCheckTimeSupport(t *testing.T) {
+	db, _ := SetupTestDB(t)
+
+	err := CheckTimeSupport(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,92 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// TestReporter is the part of testing.TB used by TrackTransactions
+type TestReporter interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...any)
+}
+
+type TxTracker struct {
+	db    *sql.DB
+	mutex sync.Mutex
+	open  []*TrackedTx
+}
+
+type TrackedTx struct {
+	*sql.Tx
+	tracker *TxTracker
+	caller  string
+}
+
+// TrackTransactions fails the test at cleanup for every transaction begun
+// through the tracker that was neither committed nor rolled back
+func TrackTransactions(t TestReporter, db *sql.DB) *TxTracker {
+	t.Helper()
+	tracker := &TxTracker{db: db}
+	t.Cleanup(func() {
+		tracker.mutex.Lock()
+		defer tracker.mutex.Unlock()
+		if len(tracker.open) == 0 {
+			return
+		}
+
+		callers := make([]string, len(tracker.open))
+		for i, tx := range tracker.open {
+			callers[i] = tx.caller
+			// kembalikan koneksinya ke pool
+			tx.Tx.Rollback()
+		}
+		t.Errorf("%d transaction(s) never committed or rolled back, begun at: %s", len(callers), strings.Join(callers, ", "))
+		tracker.open = nil
+	})
+	return tracker
+}
+
+func (tracker *TxTracker) BeginTx(ctx context.Context, opts *sql.TxOptions) (*TrackedTx, error) {
+	tx, err := tracker.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	caller := "unknown"
+	if _, file, line, ok := runtime.Caller(1); ok {
+		caller = fmt.Sprintf("%s:%d", file, line)
+	}
+	tracked := &TrackedTx{Tx: tx, tracker: tracker, caller: caller}
+
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	tracker.open = append(tracker.open, tracked)
+	return tracked, nil
+}
+
+func (tx *TrackedTx) resolve() {
+	tx.tracker.mutex.Lock()
+	defer tx.tracker.mutex.Unlock()
+	for i, open := range tx.tracker.open {
+		if open == tx {
+			tx.tracker.open = append(tx.tracker.open[:i], tx.tracker.open[i+1:]...)
+			return
+		}
+	}
+}
+
+func (tx *TrackedTx) Commit() error {
+	tx.resolve()
+	return tx.Tx.Commit()
+}
+
+func (tx *TrackedTx) Rollback() error {
+	tx.resolve()
+	return tx.Tx.Rollback()
+}
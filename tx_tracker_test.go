@@ -0,0 +1,72 @@
+package belajargolangdatabase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type fakeReporter struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (reporter *fakeReporter) Helper() {}
+
+func (reporter *fakeReporter) Cleanup(cleanup func()) {
+	reporter.cleanups = append(reporter.cleanups, cleanup)
+}
+
+func (reporter *fakeReporter) Errorf(format string, args ...any) {
+	reporter.errors = append(reporter.errors, fmt.Sprintf(format, args...))
+}
+
+func (reporter *fakeReporter) runCleanups() {
+	for i := len(reporter.cleanups) - 1; i >= 0; i-- {
+		reporter.cleanups[i]()
+	}
+}
+
+func TestTrackTransactionsFlagsLeak(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	reporter := &fakeReporter{}
+	tracker := TrackTransactions(reporter, db)
+
+	_, err := tracker.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reporter.runCleanups()
+
+	if len(reporter.errors) != 1 || !strings.Contains(reporter.errors[0], "tx_tracker_test.go") {
+		t.Fatalf("expected the leaked transaction to be reported, got %v", reporter.errors)
+	}
+}
+
+func TestTrackTransactionsResolved(t *testing.T) {
+	db, _ := SetupTestDB(t)
+	reporter := &fakeReporter{}
+	tracker := TrackTransactions(reporter, db)
+	ctx := context.Background()
+
+	committed, err := tracker.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := committed.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	rolledBack, err := tracker.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rolledBack.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	reporter.runCleanups()
+
+	if len(reporter.errors) != 0 {
+		t.Fatalf("expected no leaks, got %v", reporter.errors)
+	}
+}